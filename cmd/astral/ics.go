@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sj14/astral/pkg/astral"
+)
+
+// icsEvent is a VEVENT of the calendar. Instantaneous events start and end at the same time.
+type icsEvent struct {
+	uid     string
+	summary string
+	start   time.Time
+	end     time.Time
+}
+
+// Periods which can be exported as a single VEVENT from their start to their end.
+var icsPeriods = map[string]struct {
+	summary    string
+	start, end astral.EventKind
+}{
+	"golden_hour_rising":  {summary: "Golden Hour (Rising)", start: astral.EventGoldenHourRisingStart, end: astral.EventGoldenHourRisingEnd},
+	"golden_hour_setting": {summary: "Golden Hour (Setting)", start: astral.EventGoldenHourSettingStart, end: astral.EventGoldenHourSettingEnd},
+	"blue_hour_rising":    {summary: "Blue Hour (Rising)", start: astral.EventBlueHourRisingStart, end: astral.EventBlueHourRisingEnd},
+	"blue_hour_setting":   {summary: "Blue Hour (Setting)", start: astral.EventBlueHourSettingStart, end: astral.EventBlueHourSettingEnd},
+}
+
+// The identifiers of the lunar phases, which must never change like the ones of the events.
+var phaseIDs = map[astral.LunarPhase]string{
+	astral.LunarPhaseNew:          "new_moon",
+	astral.LunarPhaseFirstQuarter: "first_quarter",
+	astral.LunarPhaseFull:         "full_moon",
+	astral.LunarPhaseLastQuarter:  "last_quarter",
+}
+
+// icsSelection are the events selected for the calendar by their identifiers.
+type icsSelection struct {
+	kinds   []astral.EventKind
+	periods []string
+	phases  map[astral.LunarPhase]bool
+	lunar   bool
+}
+
+// parseICSEvents parses a comma separated list of event identifiers,
+// e.g. "sunrise,golden_hour_setting,full_moon".
+func parseICSEvents(list string) (icsSelection, error) {
+	// sunrise_horizon and sunset_horizon aren't offered, the calendar is
+	// calculated without the profile of -horizon
+	kinds := make(map[string]astral.EventKind)
+	for kind := astral.EventDawnAstronomical; kind <= astral.EventMoonset; kind++ {
+		kinds[eventID(kind)] = kind
+	}
+	phases := make(map[string]astral.LunarPhase)
+	for phase, id := range phaseIDs {
+		phases[id] = phase
+	}
+
+	selection := icsSelection{phases: make(map[astral.LunarPhase]bool)}
+	for _, id := range strings.Split(list, ",") {
+		id = strings.TrimSpace(id)
+		if kind, ok := kinds[id]; ok {
+			selection.kinds = append(selection.kinds, kind)
+			if kind == astral.EventMoonrise || kind == astral.EventMoonset {
+				selection.lunar = true
+			}
+			continue
+		}
+		if _, ok := icsPeriods[id]; ok {
+			selection.periods = append(selection.periods, id)
+			continue
+		}
+		if phase, ok := phases[id]; ok {
+			selection.phases[phase] = true
+			continue
+		}
+		return icsSelection{}, fmt.Errorf("unknown event %q", id)
+	}
+	return selection, nil
+}
+
+// icsUID returns an identifier which stays the same when the calendar is exported again,
+// so calendar applications update the event instead of adding a duplicate.
+func icsUID(day time.Time, id string, observer astral.Observer) string {
+	return fmt.Sprintf("%v-%v-%.4f-%.4f@astral", day.Format("20060102"), id, observer.Latitude, observer.Longitude)
+}
+
+// collectICSEvents calculates the selected events from the date of from to the date of to, ordered by time.
+func collectICSEvents(observer astral.Observer, from, to time.Time, selection icsSelection) []icsEvent {
+	var result []icsEvent
+	observer = observerIn(observer, from.Location())
+	for _, day := range days(from, to) {
+		events := astral.DayEvents(observer, day, astral.DayEventsOptions{Lunar: selection.lunar})
+
+		for _, kind := range selection.kinds {
+			if event := events.Get(kind); event.Err == nil {
+				uid := icsUID(day, eventID(kind), observer)
+				result = append(result, icsEvent{uid: uid, summary: kind.String(), start: event.Time, end: event.Time})
+			}
+		}
+
+		for _, id := range selection.periods {
+			period := icsPeriods[id]
+			start, end := events.Get(period.start), events.Get(period.end)
+			if start.Err == nil && end.Err == nil {
+				uid := icsUID(day, id, observer)
+				result = append(result, icsEvent{uid: uid, summary: period.summary, start: start.Time, end: end.Time})
+			}
+		}
+	}
+
+	if len(selection.phases) > 0 {
+		start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+		end := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, to.Location())
+		for _, phase := range astral.LunarPhases(start, end) {
+			if selection.phases[phase.Phase] {
+				uid := icsUID(phase.Time.In(from.Location()), phaseIDs[phase.Phase], observer)
+				result = append(result, icsEvent{uid: uid, summary: phase.Phase.String(), start: phase.Time, end: phase.Time})
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].start.Before(result[j].start)
+	})
+	return result
+}
+
+// zoneName returns the IANA name of the location, or an empty string when
+// the location has no such name, e.g. a fixed offset or UTC.
+func zoneName(loc *time.Location) string {
+	name := loc.String()
+	if name == "Local" {
+		name = localZoneName()
+	}
+	switch name {
+	case "", "Local", "UTC", "Etc/UTC":
+		return ""
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return ""
+	}
+	return name
+}
+
+// localZoneName returns the IANA name of the local time zone
+// from the TZ environment variable or the /etc/localtime link.
+func localZoneName() string {
+	if tz, ok := os.LookupEnv("TZ"); ok {
+		return strings.TrimPrefix(tz, ":")
+	}
+	target, err := os.Readlink("/etc/localtime")
+	if err != nil {
+		return ""
+	}
+	if i := strings.Index(target, "zoneinfo/"); i >= 0 {
+		return target[i+len("zoneinfo/"):]
+	}
+	return ""
+}
+
+// icsWriter writes content lines with CRLF endings, folded after 75 octets.
+type icsWriter struct {
+	w *bufio.Writer
+}
+
+func (w icsWriter) line(format string, args ...any) {
+	line := fmt.Sprintf(format, args...)
+	for len(line) > 75 {
+		// don't split multi-byte characters
+		i := 75
+		for i > 0 && line[i]&0xC0 == 0x80 {
+			i--
+		}
+		w.w.WriteString(line[:i] + "\r\n")
+		line = " " + line[i:]
+	}
+	w.w.WriteString(line + "\r\n")
+}
+
+// escapeText escapes the special characters of a TEXT value.
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// formatOffset formats a UTC offset in seconds as ±hhmm or ±hhmmss.
+func formatOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	if offset%60 != 0 {
+		return fmt.Sprintf("%v%02d%02d%02d", sign, offset/3600, offset/60%60, offset%60)
+	}
+	return fmt.Sprintf("%v%02d%02d", sign, offset/3600, offset/60%60)
+}
+
+// writeTimezone writes a VTIMEZONE with the offsets of the location between start and end.
+// Each transition gets its own observance instead of a recurrence rule.
+func (w icsWriter) writeTimezone(name string, loc *time.Location, start, end time.Time) {
+	type observance struct {
+		start      int64
+		offsetFrom int
+		offsetTo   int
+	}
+
+	_, offset := start.In(loc).Zone()
+	observances := []observance{{start: start.Unix(), offsetFrom: offset, offsetTo: offset}}
+
+	prev := start.Unix()
+	for t := prev + 3600; t < end.Unix()+3600; t += 3600 {
+		_, prevOffset := time.Unix(prev, 0).In(loc).Zone()
+		if _, offset := time.Unix(t, 0).In(loc).Zone(); offset != prevOffset {
+			// search the first second of the new offset
+			lo, hi := prev, t
+			for hi-lo > 1 {
+				mid := lo + (hi-lo)/2
+				if _, o := time.Unix(mid, 0).In(loc).Zone(); o == prevOffset {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			observances = append(observances, observance{start: hi, offsetFrom: prevOffset, offsetTo: offset})
+		}
+		prev = t
+	}
+
+	w.line("BEGIN:VTIMEZONE")
+	w.line("TZID:%v", name)
+	for _, o := range observances {
+		t := time.Unix(o.start, 0).In(loc)
+		component := "STANDARD"
+		if t.IsDST() {
+			component = "DAYLIGHT"
+		}
+		abbreviation, _ := t.Zone()
+
+		w.line("BEGIN:%v", component)
+		// the onset is given in the local time before the transition
+		w.line("DTSTART:%v", time.Unix(o.start, 0).In(time.FixedZone("", o.offsetFrom)).Format("20060102T150405"))
+		w.line("TZOFFSETFROM:%v", formatOffset(o.offsetFrom))
+		w.line("TZOFFSETTO:%v", formatOffset(o.offsetTo))
+		w.line("TZNAME:%v", escapeText(abbreviation))
+		w.line("END:%v", component)
+	}
+	w.line("END:VTIMEZONE")
+}
+
+// writeICS writes the selected events from the date of from to the date of to as iCalendar.
+// The times refer to the IANA time zone of from, or to UTC if it has none.
+func writeICS(out io.Writer, observer astral.Observer, from, to time.Time, selection icsSelection) error {
+	loc := from.Location()
+	tzid := zoneName(loc)
+	if tzid != "" {
+		// use the zone by its name, e.g. instead of "Local"
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return err
+		}
+	}
+
+	events := collectICSEvents(observer, from, to, selection)
+
+	w := icsWriter{w: bufio.NewWriter(out)}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//sj14//astral %v//EN", version)
+	w.line("CALSCALE:GREGORIAN")
+
+	formatTime := func(property string, t time.Time) {
+		if tzid == "" {
+			w.line("%v:%v", property, t.UTC().Format("20060102T150405Z"))
+			return
+		}
+		w.line("%v;TZID=%v:%v", property, tzid, t.In(loc).Format("20060102T150405"))
+	}
+
+	if tzid != "" {
+		start := time.Date(from.Year(), from.Month(), from.Day()-1, 0, 0, 0, 0, loc)
+		end := time.Date(to.Year(), to.Month(), to.Day()+2, 0, 0, 0, 0, loc)
+		w.writeTimezone(tzid, loc, start, end)
+	}
+
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	for _, event := range events {
+		w.line("BEGIN:VEVENT")
+		w.line("UID:%v", event.uid)
+		w.line("DTSTAMP:%v", stamp)
+		formatTime("DTSTART", event.start.Truncate(time.Second))
+		formatTime("DTEND", event.end.Truncate(time.Second))
+		w.line("SUMMARY:%v", escapeText(event.summary))
+		w.line("GEO:%.6f;%.6f", observer.Latitude, observer.Longitude)
+		w.line("TRANSP:TRANSPARENT")
+		w.line("END:VEVENT")
+	}
+	w.line("END:VCALENDAR")
+
+	return w.w.Flush()
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sj14/astral/pkg/astral"
+)
+
+func TestParseICSEvents(t *testing.T) {
+	selection, err := parseICSEvents("sunrise, golden_hour_setting,full_moon,moonrise")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selection.kinds) != 2 || selection.kinds[0] != astral.EventSunrise || selection.kinds[1] != astral.EventMoonrise {
+		t.Fatalf("unexpected kinds %v", selection.kinds)
+	}
+	if len(selection.periods) != 1 || selection.periods[0] != "golden_hour_setting" {
+		t.Fatalf("unexpected periods %v", selection.periods)
+	}
+	if len(selection.phases) != 1 || !selection.phases[astral.LunarPhaseFull] {
+		t.Fatalf("unexpected phases %v", selection.phases)
+	}
+	if !selection.lunar {
+		t.Fatal("expected the lunar events to be calculated")
+	}
+
+	for _, list := range []string{"sunrise,unknown", "Sunrise", "sunrise_horizon"} {
+		if _, err := parseICSEvents(list); err == nil {
+			t.Errorf("%q: expected an error", list)
+		}
+	}
+}
+
+func TestPhaseIDs(t *testing.T) {
+	for _, phase := range []astral.LunarPhase{astral.LunarPhaseNew, astral.LunarPhaseFirstQuarter, astral.LunarPhaseFull, astral.LunarPhaseLastQuarter} {
+		if phaseIDs[phase] == "" {
+			t.Fatalf("%v has no identifier", phase)
+		}
+	}
+}
+
+func TestICSUID(t *testing.T) {
+	observer := astral.Observer{Latitude: 51.58, Longitude: 6.52}
+	day := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// the identifier must not change between releases
+	if got, want := icsUID(day, "sunrise", observer), "20260101-sunrise-51.5800-6.5200@astral"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// exporting the calendar again results in the same identifiers
+	selection, err := parseICSEvents("sunrise,golden_hour_setting,full_moon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, to := day, day.AddDate(0, 1, 0)
+	first := collectICSEvents(observer, from, to, selection)
+	second := collectICSEvents(observer, from, to, selection)
+	if len(first) != len(second) {
+		t.Fatalf("got %v and %v events", len(first), len(second))
+	}
+	seen := make(map[string]bool)
+	for i := range first {
+		if first[i].uid != second[i].uid {
+			t.Fatalf("got %v and %v", first[i].uid, second[i].uid)
+		}
+		if seen[first[i].uid] {
+			t.Fatalf("duplicate identifier %v", first[i].uid)
+		}
+		seen[first[i].uid] = true
+	}
+	if !seen["20260103-full_moon-51.5800-6.5200@astral"] {
+		t.Fatalf("missing full moon in %v", seen)
+	}
+}
+
+func TestICSWriterLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "short",
+			line: "BEGIN:VEVENT",
+			want: "BEGIN:VEVENT\r\n",
+		},
+		{
+			name: "exactly 75 octets",
+			line: strings.Repeat("a", 75),
+			want: strings.Repeat("a", 75) + "\r\n",
+		},
+		{
+			name: "folded",
+			line: strings.Repeat("a", 160),
+			want: strings.Repeat("a", 75) + "\r\n " + strings.Repeat("a", 74) + "\r\n " + strings.Repeat("a", 11) + "\r\n",
+		},
+		{
+			name: "multi-byte character at the fold",
+			line: strings.Repeat("a", 74) + "äb",
+			want: strings.Repeat("a", 74) + "\r\n äb\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := icsWriter{w: bufio.NewWriter(&buf)}
+			w.line("%v", tt.line)
+			if err := w.w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+			for _, line := range strings.SplitAfter(buf.String(), "\r\n") {
+				if len(strings.TrimSuffix(line, "\r\n")) > 75 {
+					t.Fatalf("line %q is longer than 75 octets", line)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteTimezone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w := icsWriter{w: bufio.NewWriter(&buf)}
+	start := time.Date(2026, 3, 28, 0, 0, 0, 0, berlin)
+	end := time.Date(2026, 3, 31, 0, 0, 0, 0, berlin)
+	w.writeTimezone("Europe/Berlin", berlin, start, end)
+	if err := w.w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Berlin",
+		"BEGIN:STANDARD",
+		"DTSTART:20260328T000000",
+		"TZOFFSETFROM:+0100",
+		"TZOFFSETTO:+0100",
+		"TZNAME:CET",
+		"END:STANDARD",
+		// the clocks are set forward at 02:00 CET
+		"BEGIN:DAYLIGHT",
+		"DTSTART:20260329T020000",
+		"TZOFFSETFROM:+0100",
+		"TZOFFSETTO:+0200",
+		"TZNAME:CEST",
+		"END:DAYLIGHT",
+		"END:VTIMEZONE",
+		"",
+	}, "\r\n")
+	if got := buf.String(); got != want {
+		t.Fatalf("got\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestCollectICSEventsFarEast(t *testing.T) {
+	auckland, err := time.LoadLocation("Pacific/Auckland")
+	if err != nil {
+		t.Fatal(err)
+	}
+	observer := astral.Observer{Latitude: -36.85, Longitude: 174.76}
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, auckland)
+
+	selection, err := parseICSEvents("sunrise")
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := collectICSEvents(observer, day, day, selection)
+	if len(events) != 1 {
+		t.Fatalf("got %v events, want 1", len(events))
+	}
+
+	// the sunrise of the date in NZDT (UTC+13), not of the UTC date of its noon
+	located := observer
+	located.Location = auckland
+	want, err := astral.Sunrise(located, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !events[0].start.Equal(want) {
+		t.Fatalf("got %v, want %v", events[0].start, want)
+	}
+}
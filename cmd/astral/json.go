@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/sj14/astral/pkg/astral"
+)
+
+// The JSON output, see the README for the schema.
+// Fields may be added, but existing fields keep their names and meaning.
+type jsonOutput struct {
+	Observer jsonObserver `json:"observer"`
+	Time     string       `json:"time"`
+	// Durations in seconds, null when the sun doesn't rise or set.
+	Daylight *float64    `json:"daylight"`
+	Night    *float64    `json:"night"`
+	Moon     jsonMoon    `json:"moon"`
+	Events   []jsonEvent `json:"events"`
+}
+
+type jsonObserver struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Elevation float64 `json:"elevation"`
+	// IANA time zone of the observer, omitted when not set with -tz or -city.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+type jsonMoon struct {
+	Phase        string  `json:"phase"`
+	Illumination float64 `json:"illumination"`
+}
+
+type jsonEvent struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// RFC3339 time of the event, omitted when it doesn't occur.
+	Time string `json:"time,omitempty"`
+	// Reason why the event doesn't occur, omitted when it occurs.
+	Reason      string `json:"reason,omitempty"`
+	AlwaysAbove bool   `json:"always_above,omitempty"`
+	AlwaysBelow bool   `json:"always_below,omitempty"`
+}
+
+// The identifiers of the events in the JSON and ics output and of the -events flag.
+// They must never change, unlike the names which are only meant for display.
+var eventIDs = map[astral.EventKind]string{
+	astral.EventDawnAstronomical:       "dawn_astronomical",
+	astral.EventDawnNautical:           "dawn_nautical",
+	astral.EventDawnCivil:              "dawn_civil",
+	astral.EventBlueHourRisingStart:    "blue_hour_start_rising",
+	astral.EventBlueHourRisingEnd:      "blue_hour_end_rising",
+	astral.EventGoldenHourRisingStart:  "golden_hour_start_rising",
+	astral.EventSunrise:                "sunrise",
+	astral.EventGoldenHourRisingEnd:    "golden_hour_end_rising",
+	astral.EventNoon:                   "noon",
+	astral.EventGoldenHourSettingStart: "golden_hour_start_setting",
+	astral.EventSunset:                 "sunset",
+	astral.EventGoldenHourSettingEnd:   "golden_hour_end_setting",
+	astral.EventBlueHourSettingStart:   "blue_hour_start_setting",
+	astral.EventBlueHourSettingEnd:     "blue_hour_end_setting",
+	astral.EventDuskCivil:              "dusk_civil",
+	astral.EventDuskNautical:           "dusk_nautical",
+	astral.EventDuskAstronomical:       "dusk_astronomical",
+	astral.EventMidnight:               "midnight",
+	astral.EventRahukaalamStart:        "rahukaalam_start",
+	astral.EventRahukaalamEnd:          "rahukaalam_end",
+	astral.EventMoonrise:               "moonrise",
+	astral.EventMoonset:                "moonset",
+	astral.EventHorizonSunrise:         "sunrise_horizon",
+	astral.EventHorizonSunset:          "sunset_horizon",
+}
+
+// eventID returns the identifier of the event, e.g. "dawn_civil" for "Dawn (Civil)".
+func eventID(kind astral.EventKind) string {
+	return eventIDs[kind]
+}
+
+// durationSeconds returns the seconds between start and end,
+// or nil when one of them is missing.
+func durationSeconds(start, end astral.Event) *float64 {
+	if start.Err != nil || end.Err != nil {
+		return nil
+	}
+	seconds := end.Time.Sub(start.Time).Truncate(time.Second).Seconds()
+	return &seconds
+}
+
+func writeJSON(w io.Writer, observer astral.Observer, t time.Time, events astral.Events, sunriseNextDay astral.Event) error {
+	out := jsonOutput{
+		Observer: jsonObserver{
+			Latitude:  observer.Latitude,
+			Longitude: observer.Longitude,
+			Elevation: observer.Elevation,
+		},
+		Time:     t.Format(time.RFC3339),
+		Daylight: durationSeconds(events.Get(astral.EventSunrise), events.Get(astral.EventSunset)),
+		Night:    durationSeconds(events.Get(astral.EventSunset), sunriseNextDay),
+		Moon: jsonMoon{
+			Phase:        astral.MoonPhaseNameAt(t).String(),
+			Illumination: astral.MoonIllumination(t).Fraction,
+		},
+		Events: []jsonEvent{},
+	}
+
+	if observer.Location != nil {
+		out.Observer.Timezone = observer.Location.String()
+	}
+
+	for _, event := range events {
+		e := jsonEvent{ID: eventID(event.Kind), Name: event.Kind.String()}
+		if event.Err != nil {
+			e.Reason = event.Err.Error()
+			var eventErr *astral.EventError
+			if errors.As(event.Err, &eventErr) {
+				e.AlwaysAbove = eventErr.AlwaysAbove
+				e.AlwaysBelow = eventErr.AlwaysBelow
+			}
+		} else {
+			e.Time = event.Time.In(t.Location()).Format(time.RFC3339)
+		}
+		out.Events = append(out.Events, e)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sj14/astral/pkg/astral"
+)
+
+func TestEventIDs(t *testing.T) {
+	// the kind after the last one has no name, otherwise a new kind is missing here
+	if name := (astral.EventHorizonSunset + 1).String(); !strings.HasPrefix(name, "EventKind(") {
+		t.Fatalf("unexpected kind %v after the last one, add its identifier", name)
+	}
+
+	seen := map[string]astral.EventKind{}
+	for kind := astral.EventDawnAstronomical; kind <= astral.EventHorizonSunset; kind++ {
+		id := eventID(kind)
+		if id == "" {
+			t.Fatalf("%v has no identifier", kind)
+		}
+		if other, ok := seen[id]; ok {
+			t.Fatalf("%v and %v have the same identifier %q", kind, other, id)
+		}
+		seen[id] = kind
+	}
+
+	// identifiers which are part of the output and must stay the same
+	tests := []struct {
+		kind astral.EventKind
+		want string
+	}{
+		{kind: astral.EventDawnCivil, want: "dawn_civil"},
+		{kind: astral.EventGoldenHourSettingStart, want: "golden_hour_start_setting"},
+		{kind: astral.EventHorizonSunrise, want: "sunrise_horizon"},
+	}
+	for _, tt := range tests {
+		if got := eventID(tt.kind); got != tt.want {
+			t.Errorf("%v: got %q, want %q", tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	observer := astral.Observer{Latitude: 78.22, Longitude: 15.65}
+	date := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	events := astral.DayEvents(observer, date, astral.DayEventsOptions{})
+	sunriseNextDay := astral.DayEvents(observer, date.AddDate(0, 0, 1), astral.DayEventsOptions{}).Get(astral.EventSunrise)
+
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, observer, date, events, sunriseNextDay); err != nil {
+		t.Fatal(err)
+	}
+
+	var out jsonOutput
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Daylight != nil {
+		t.Fatalf("got daylight %v during the polar night", *out.Daylight)
+	}
+	for _, e := range out.Events {
+		if e.ID != "sunrise" {
+			continue
+		}
+		if e.Time != "" || e.Reason == "" || !e.AlwaysBelow {
+			t.Fatalf("unexpected sunrise %+v", e)
+		}
+		return
+	}
+	t.Fatal("sunrise is missing")
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -9,6 +10,7 @@ import (
 	"sort"
 	"strings"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/logrusorgru/aurora/v4"
 	"github.com/sj14/astral/pkg/astral"
@@ -26,10 +28,21 @@ func main() {
 		dateTimeFormat = "Jan _2 15:04"
 		timeFormat     = "15:04"
 
-		timeFlag      = flag.String("time", time.Now().Format(time.RFC3339), "day/time used for the calculation")
+		timeFlag      = flag.String("time", time.Now().Format(time.RFC3339), "day/time used for the calculation (RFC3339, or YYYY-MM-DD[THH:MM[:SS]] in the time zone)")
 		latFlag       = flag.Float64("lat", 0, "latitude of the observer")
 		longFlag      = flag.Float64("long", 0, "longitude of the observer")
 		elevationFlag = flag.Float64("elev", 0, "elevation of the observer")
+		cityFlag      = flag.String("city", "", "name of the city instead of -lat, -long and -elev, e.g. \"Berlin\" or \"London, Canada\"")
+		tzFlag        = flag.String("tz", "", "IANA time zone of the observer, e.g. \"Asia/Tokyo\" (defaults to the zone of the city or the local zone)")
+		pressureFlag  = flag.Float64("pressure", 1010, "air pressure in hPa for the refraction")
+		tempFlag      = flag.Float64("temp", 10, "air temperature in degrees Celsius for the refraction")
+		noRefractFlag = flag.Bool("no-refraction", false, "disable the refraction")
+		horizonFlag   = flag.String("horizon", "", "CSV file with the horizon profile (azimuth,altitude per line)")
+		solarFlag     = flag.Bool("solar", false, "print the local mean and apparent solar time")
+		formatFlag    = flag.String("format", "text", "output format (text, json, csv, tsv, ics)")
+		fromFlag      = flag.String("from", "", "first day (YYYY-MM-DD) of the csv, tsv and ics output")
+		toFlag        = flag.String("to", "", "last day (YYYY-MM-DD) of the csv, tsv and ics output")
+		eventsFlag    = flag.String("events", "sunrise,sunset", "comma separated events of the ics output")
 		versionFlag   = flag.Bool("version", false, fmt.Sprintf("print version information of this release (%v)", version))
 	)
 	flag.Parse()
@@ -41,92 +54,130 @@ func main() {
 		os.Exit(0)
 	}
 
-	observer := astral.Observer{Latitude: *latFlag, Longitude: *longFlag, Elevation: *elevationFlag}
-
-	t, err := time.Parse(time.RFC3339, *timeFlag)
-	if err != nil {
-		log.Fatalf("failed parsing time: %v\n", err)
+	observer := astral.Observer{
+		Latitude:  *latFlag,
+		Longitude: *longFlag,
+		Elevation: *elevationFlag,
 	}
+	loc := time.Local
+	cityName := ""
+	if *cityFlag != "" {
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "lat" || f.Name == "long" || f.Name == "elev" {
+				log.Fatalf("-city can't be combined with -%v\n", f.Name)
+			}
+		})
 
-	dawnCivil, err := astral.Dawn(observer, t, astral.DepressionCivil)
-	if err != nil {
-		log.Println(err)
+		city, err := astral.FindCity(*cityFlag)
+		if err != nil {
+			log.Fatalf("failed looking up city: %v\n", err)
+		}
+		if loc, err = city.Location(); err != nil {
+			log.Fatalf("failed loading time zone: %v\n", err)
+		}
+		observer = city.Observer()
+		cityName = city.String()
 	}
-	dawnAstronomical, err := astral.Dawn(observer, t, astral.DepressionAstronomical)
-	if err != nil {
-		log.Println(err)
+	if *tzFlag != "" {
+		var err error
+		if loc, err = time.LoadLocation(*tzFlag); err != nil {
+			log.Fatalf("failed loading time zone: %v\n", err)
+		}
 	}
-	dawnNautical, err := astral.Dawn(observer, t, astral.DepressionNautical)
-	if err != nil {
-		log.Println(err)
+	// without a zone, the times stay in the offset of -time
+	if cityName != "" || *tzFlag != "" {
+		observer.Location = loc
 	}
-
-	goldenRisingStart, goldenRisingEnd, err := astral.GoldenHour(observer, t, astral.SunDirectionRising)
-	if err != nil {
-		log.Println(err)
+	observer.Atmosphere = &astral.Atmosphere{Pressure: *pressureFlag, Temperature: *tempFlag}
+	if err := observer.Atmosphere.Validate(); err != nil {
+		log.Fatalf("invalid -pressure or -temp: %v\n", err)
 	}
+	observer.NoRefraction = *noRefractFlag
 
-	sunrise, err := astral.Sunrise(observer, t)
+	t, err := parseTime(*timeFlag, loc)
 	if err != nil {
-		log.Println(err)
+		log.Fatalf("failed parsing time: %v\n", err)
 	}
-
-	sunriseNextDay, err := astral.Sunrise(observer, t.Add(24*time.Hour))
-	if err != nil {
-		log.Println(err)
+	if observer.Location != nil {
+		t = t.In(loc)
 	}
 
-	noon := astral.Noon(observer, t)
-
-	goldenSettingStart, goldenSettingEnd, err := astral.GoldenHour(observer, t, astral.SunDirectionSetting)
-	if err != nil {
-		log.Println(err)
+	switch *formatFlag {
+	case "text", "json":
+		if *fromFlag != "" || *toFlag != "" {
+			log.Fatalf("-from and -to are only supported by the csv, tsv and ics format\n")
+		}
+	case "ics":
+		selection, err := parseICSEvents(*eventsFlag)
+		if err != nil {
+			log.Fatalf("failed parsing events: %v\n", err)
+		}
+		from, to, err := parseRange(*fromFlag, *toFlag, t, loc)
+		if err != nil {
+			log.Fatalf("failed parsing range: %v\n", err)
+		}
+		if err := writeICS(os.Stdout, observer, from, to, selection); err != nil {
+			log.Fatalf("failed writing ics: %v\n", err)
+		}
+		return
+	case "csv", "tsv":
+		from, to, err := parseRange(*fromFlag, *toFlag, t, loc)
+		if err != nil {
+			log.Fatalf("failed parsing range: %v\n", err)
+		}
+		delimiter := ','
+		if *formatFlag == "tsv" {
+			delimiter = '\t'
+		}
+		if err := writeTable(os.Stdout, delimiter, observer, from, to); err != nil {
+			log.Fatalf("failed writing %v: %v\n", *formatFlag, err)
+		}
+		return
+	default:
+		log.Fatalf("unknown format %q\n", *formatFlag)
 	}
 
-	sunset, err := astral.Sunset(observer, t)
-	if err != nil {
-		log.Println(err)
+	opts := astral.DayEventsOptions{Lunar: true}
+	if *horizonFlag != "" {
+		horizon, err := readHorizon(*horizonFlag)
+		if err != nil {
+			log.Fatalf("failed reading horizon: %v\n", err)
+		}
+		opts.Horizon = &horizon
 	}
 
-	duskCivil, err := astral.Dusk(observer, t, astral.DepressionCivil)
-	if err != nil {
-		log.Println(err)
+	events := astral.DayEvents(observer, t, opts)
+	sunriseNextDay, err := astral.Sunrise(observer, t.AddDate(0, 0, 1))
+
+	if *formatFlag == "json" {
+		next := astral.Event{Kind: astral.EventSunrise, Time: sunriseNextDay, Err: err}
+		if err := writeJSON(os.Stdout, observer, t, events, next); err != nil {
+			log.Fatalf("failed writing json: %v\n", err)
+		}
+		return
 	}
-	duskAstronomical, err := astral.Dusk(observer, t, astral.DepressionAstronomical)
+
 	if err != nil {
 		log.Println(err)
 	}
-	duskNautical, err := astral.Dusk(observer, t, astral.DepressionNautical)
-	if err != nil {
-		log.Println(err)
+	for _, event := range events {
+		if event.Err != nil {
+			log.Printf("%v: %v\n", event.Kind, event.Err)
+		}
 	}
 
-	midnight := astral.Midnight(observer, t)
-
-	moonPhase := astral.MoonPhase(t)
-	moonDesc, err := astral.MoonPhaseDescription(moonPhase)
-	if err != nil {
-		log.Fatalf("failed parsing moon phase: %v", err)
-	}
+	sunrise := events.Get(astral.EventSunrise).Time
+	sunset := events.Get(astral.EventSunset).Time
 
 	dashes := "┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈"
 
 	dates := make(map[time.Time]colorDesc)
 	dates[t] = colorDesc{desc: dashes}
-	dates[dawnAstronomical] = colorDesc{color: aurora.BgGray(8, " "), desc: "Dawn (Astronomical)"}
-	dates[dawnNautical] = colorDesc{color: aurora.BgGray(15, " "), desc: "Dawn (Nautical)"}
-	dates[dawnCivil] = colorDesc{color: aurora.BgIndex(111, " "), desc: "Dawn (Civil)         Twilight Start    Blue Hour Start"}
-	dates[goldenRisingStart] = colorDesc{color: aurora.BgIndex(208, " "), desc: "Golden Hour Start                      Blue Hour End"}
-	dates[sunrise] = colorDesc{color: aurora.BgIndex(214, " "), desc: "Sunrise              Twilight End"}
-	dates[goldenRisingEnd] = colorDesc{color: aurora.BgIndex(226, " "), desc: "Golden Hour End"}
-	dates[noon] = colorDesc{color: aurora.BgIndex(226, " "), desc: "Noon"}
-	dates[goldenSettingStart] = colorDesc{color: aurora.BgIndex(214, " "), desc: "Golden Hour Start"}
-	dates[sunset] = colorDesc{color: aurora.BgIndex(208, " "), desc: "Sunset               Twilight Start"}
-	dates[goldenSettingEnd] = colorDesc{color: aurora.BgIndex(111, " "), desc: "Golden Hour End                        Blue Hour Start"}
-	dates[duskCivil] = colorDesc{color: aurora.BgGray(18, " "), desc: "Dusk (Civil)         Twilight End      Blue Hour End "}
-	dates[duskNautical] = colorDesc{color: aurora.BgGray(15, " "), desc: "Dusk (Nautical)"}
-	dates[duskAstronomical] = colorDesc{color: aurora.BgGray(8, " "), desc: "Dusk (Astronomical)"}
-	dates[midnight] = colorDesc{color: aurora.BgBlack(" "), desc: "Midnight"}
+	for _, event := range events.Occurring() {
+		if desc, ok := eventDescs[event.Kind]; ok {
+			dates[event.Time] = desc
+		}
+	}
 
 	var sortedTimes timeSlice
 
@@ -136,11 +187,23 @@ func main() {
 	sort.Sort(sortedTimes)
 
 	fmt.Printf("Date/Time\t%v\n", t.Format(time.UnixDate))
-	fmt.Printf("Latitude\t%v\nLongitude\t%v\nElevation\t%v\n", *latFlag, *longFlag, *elevationFlag)
+	if *solarFlag {
+		fmt.Printf("Mean Solar\t%v\n", astral.MeanSolarTime(observer, t).Format(time.UnixDate))
+		fmt.Printf("Apparent Solar\t%v\n", astral.ApparentSolarTime(observer, t).Format(time.UnixDate))
+	}
+	if cityName != "" {
+		fmt.Printf("City\t%v\n", cityName)
+	}
+	fmt.Printf("Latitude\t%v\nLongitude\t%v\nElevation\t%v\n", observer.Latitude, observer.Longitude, observer.Elevation)
 	fmt.Println()
 	fmt.Printf("Daylight\t%v\n", sunset.Sub(sunrise).Truncate(1*time.Second))
 	fmt.Printf("Night-Time\t%v\n", sunriseNextDay.Sub(sunset).Truncate(1*time.Second))
-	fmt.Printf("Moon Phase\t%v (%v)\n", moonDesc, moonPhase)
+	fmt.Printf("Moon Phase\t%v (%.1f%% illuminated)\n", astral.MoonPhaseNameAt(t), astral.MoonIllumination(t).Fraction*100)
+	if phase, ok := astral.NewLunarPhaseIterator(t, t.AddDate(0, 0, 8)).Next(); ok {
+		fmt.Printf("Next Phase\t%v (%v)\n", phase.Phase, phase.Time.In(t.Location()).Format(dateTimeFormat))
+	}
+	fmt.Printf("Season\t%v\n", astral.SeasonAt(t, hemisphere(observer.Latitude)))
+	printPeriods(observer, t)
 	fmt.Println()
 
 	lastColor := aurora.BgBlack(" ")
@@ -172,14 +235,122 @@ func main() {
 			continue
 		}
 
-		lastColor = dates[key].color
+		// the moon doesn't change the colour of the sky
+		if !dates[key].lunar {
+			lastColor = dates[key].color
+		}
 		fmt.Printf("%v (%v) %v %v\n", key.Format(dateTimeFormat), agoOrUntil, dates[key].color, dates[key].desc)
 	}
 }
 
+// parseTime parses an RFC3339 time, or a date and time without offset in the given location.
+func parseTime(value string, loc *time.Location) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"} {
+		if t, errLayout := time.ParseInLocation(layout, value, loc); errLayout == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// parseRange parses the first and last day of a table in the given location.
+// Without both days, the table only contains the day of t.
+func parseRange(from, to string, t time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	if from == "" && to == "" {
+		return t, t, nil
+	}
+	if from == "" || to == "" {
+		return time.Time{}, time.Time{}, errors.New("both -from and -to are needed")
+	}
+
+	start, err := time.ParseInLocation("2006-01-02", from, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := time.ParseInLocation("2006-01-02", to, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%v is before %v", to, from)
+	}
+	return start, end, nil
+}
+
+// printPeriods prints the polar day or night and the periods without
+// twilight when the given time falls inside one of them.
+func printPeriods(observer astral.Observer, t time.Time) {
+	periods := []struct {
+		desc string
+		at   func() (astral.DateRange, bool)
+	}{
+		{desc: "Polar Day", at: func() (astral.DateRange, bool) { return astral.PolarDayAt(observer, t) }},
+		{desc: "Polar Night", at: func() (astral.DateRange, bool) { return astral.PolarNightAt(observer, t) }},
+		{desc: "No Civil Night", at: func() (astral.DateRange, bool) { return astral.NoDepressionAt(observer, t, astral.DepressionCivil) }},
+		{desc: "No Nautical Night", at: func() (astral.DateRange, bool) { return astral.NoDepressionAt(observer, t, astral.DepressionNautical) }},
+		{desc: "No Astro. Night", at: func() (astral.DateRange, bool) {
+			return astral.NoDepressionAt(observer, t, astral.DepressionAstronomical)
+		}},
+	}
+
+	for _, p := range periods {
+		if r, ok := p.at(); ok {
+			fmt.Printf("%v\t%v - %v\n", p.desc, r.Start.Format("Jan _2"), r.End.Format("Jan _2"))
+		}
+	}
+}
+
+// hemisphere returns the hemisphere of the given latitude.
+func hemisphere(latitude float64) astral.Hemisphere {
+	if latitude < 0 {
+		return astral.HemisphereSouthern
+	}
+	return astral.HemisphereNorthern
+}
+
+func readHorizon(path string) (astral.Horizon, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return astral.Horizon{}, err
+	}
+	defer f.Close()
+
+	return astral.ReadHorizonCSV(f)
+}
+
+// The events shown in the timeline. Events which are at the same time
+// as others, like the start of the blue hour, are part of their descriptions.
+var eventDescs = map[astral.EventKind]colorDesc{
+	astral.EventDawnAstronomical:       {color: aurora.BgGray(8, " "), desc: "Dawn (Astronomical)"},
+	astral.EventDawnNautical:           {color: aurora.BgGray(15, " "), desc: "Dawn (Nautical)"},
+	astral.EventDawnCivil:              {color: aurora.BgIndex(111, " "), desc: "Dawn (Civil)         Twilight Start    Blue Hour Start"},
+	astral.EventGoldenHourRisingStart:  {color: aurora.BgIndex(208, " "), desc: "Golden Hour Start                      Blue Hour End"},
+	astral.EventSunrise:                {color: aurora.BgIndex(214, " "), desc: "Sunrise              Twilight End"},
+	astral.EventGoldenHourRisingEnd:    {color: aurora.BgIndex(226, " "), desc: "Golden Hour End"},
+	astral.EventNoon:                   {color: aurora.BgIndex(226, " "), desc: "Noon"},
+	astral.EventRahukaalamStart:        {color: aurora.BgIndex(226, " "), desc: "Rahukaalam Start"},
+	astral.EventRahukaalamEnd:          {color: aurora.BgIndex(226, " "), desc: "Rahukaalam End"},
+	astral.EventGoldenHourSettingStart: {color: aurora.BgIndex(214, " "), desc: "Golden Hour Start"},
+	astral.EventSunset:                 {color: aurora.BgIndex(208, " "), desc: "Sunset               Twilight Start"},
+	astral.EventGoldenHourSettingEnd:   {color: aurora.BgIndex(111, " "), desc: "Golden Hour End                        Blue Hour Start"},
+	astral.EventDuskCivil:              {color: aurora.BgGray(18, " "), desc: "Dusk (Civil)         Twilight End      Blue Hour End "},
+	astral.EventDuskNautical:           {color: aurora.BgGray(15, " "), desc: "Dusk (Nautical)"},
+	astral.EventDuskAstronomical:       {color: aurora.BgGray(8, " "), desc: "Dusk (Astronomical)"},
+	astral.EventMidnight:               {color: aurora.BgBlack(" "), desc: "Midnight"},
+	astral.EventMoonrise:               {color: aurora.BgIndex(252, " "), desc: "Moonrise", lunar: true},
+	astral.EventMoonset:                {color: aurora.BgIndex(244, " "), desc: "Moonset", lunar: true},
+	astral.EventHorizonSunrise:         {color: aurora.BgIndex(214, " "), desc: "Sunrise (Horizon)"},
+	astral.EventHorizonSunset:          {color: aurora.BgIndex(208, " "), desc: "Sunset (Horizon)"},
+}
+
 type colorDesc struct {
 	color aurora.Value
 	desc  string
+	lunar bool
 }
 
 type timeSlice []time.Time
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/sj14/astral/pkg/astral"
+)
+
+var tableHeader = []string{"date", "dawn", "sunrise", "noon", "sunset", "dusk", "day_length", "reason"}
+
+// days returns the noon of each day from the date of from to the date of to, inclusive.
+// The events of these days are only calculated for the local dates with an
+// observer in the location of the days, see observerIn.
+func days(from, to time.Time) []time.Time {
+	var result []time.Time
+	last := time.Date(to.Year(), to.Month(), to.Day(), 12, 0, 0, 0, to.Location())
+	for day := time.Date(from.Year(), from.Month(), from.Day(), 12, 0, 0, 0, from.Location()); !day.After(last); day = day.AddDate(0, 0, 1) {
+		result = append(result, day)
+	}
+	return result
+}
+
+// observerIn returns the observer with the given location, unless it already has one.
+// Without a location, the calculations take the UTC date of a day, which is the
+// previous date for the noon in zones east of UTC+12, e.g. 23:00 UTC for NZDT (UTC+13).
+func observerIn(observer astral.Observer, loc *time.Location) astral.Observer {
+	if observer.Location == nil {
+		observer.Location = loc
+	}
+	return observer
+}
+
+// tableTime formats the time of the event, or returns an empty cell if it doesn't occur.
+func tableTime(event astral.Event, loc *time.Location) string {
+	if event.Err != nil {
+		return ""
+	}
+	return event.Time.In(loc).Format("15:04:05")
+}
+
+// dayLength formats the time between sunrise and sunset as hh:mm:ss.
+// During the polar day and night, it's 24:00:00 and 00:00:00.
+func dayLength(sunrise, sunset astral.Event) string {
+	var d time.Duration
+	switch {
+	case errors.Is(sunrise.Err, astral.ErrAlwaysAbove):
+		d = 24 * time.Hour
+	case errors.Is(sunrise.Err, astral.ErrAlwaysBelow):
+		d = 0
+	case sunrise.Err != nil || sunset.Err != nil:
+		return ""
+	default:
+		d = sunset.Time.Sub(sunrise.Time).Truncate(time.Second)
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+// tableReason joins the reasons why events don't occur, e.g. "sunrise: sun is always below the horizon".
+func tableReason(events ...astral.Event) string {
+	var reasons []string
+	for _, event := range events {
+		if event.Err != nil {
+			reasons = append(reasons, eventID(event.Kind)+": "+event.Err.Error())
+		}
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// writeTable writes one row with the civil dawn, sunrise, noon, sunset,
+// civil dusk, day length and the reasons of missing events per day,
+// separated by the given delimiter.
+func writeTable(w io.Writer, delimiter rune, observer astral.Observer, from, to time.Time) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = delimiter
+
+	if err := cw.Write(tableHeader); err != nil {
+		return err
+	}
+
+	observer = observerIn(observer, from.Location())
+
+	for _, day := range days(from, to) {
+		var (
+			dawn    = astral.Event{Kind: astral.EventDawnCivil}
+			sunrise = astral.Event{Kind: astral.EventSunrise}
+			noon    = astral.Event{Kind: astral.EventNoon, Time: astral.Noon(observer, day)}
+			sunset  = astral.Event{Kind: astral.EventSunset}
+			dusk    = astral.Event{Kind: astral.EventDuskCivil}
+		)
+		dawn.Time, dawn.Err = astral.Dawn(observer, day, astral.DepressionCivil)
+		sunrise.Time, sunrise.Err = astral.Sunrise(observer, day)
+		sunset.Time, sunset.Err = astral.Sunset(observer, day)
+		dusk.Time, dusk.Err = astral.Dusk(observer, day, astral.DepressionCivil)
+
+		row := []string{
+			day.Format("2006-01-02"),
+			tableTime(dawn, day.Location()),
+			tableTime(sunrise, day.Location()),
+			tableTime(noon, day.Location()),
+			tableTime(sunset, day.Location()),
+			tableTime(dusk, day.Location()),
+			dayLength(sunrise, sunset),
+			tableReason(dawn, sunrise, sunset, dusk),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sj14/astral/pkg/astral"
+)
+
+func TestWriteTable(t *testing.T) {
+	tests := []struct {
+		name      string
+		observer  astral.Observer
+		day       time.Time
+		dayLength string
+		reasons   []string
+	}{
+		{
+			name:      "london",
+			observer:  astral.Observer{Latitude: 51.5, Longitude: -0.12},
+			day:       time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+			dayLength: "16:18:25",
+		},
+		{
+			name:      "polar night",
+			observer:  astral.Observer{Latitude: 78.22, Longitude: 15.65},
+			day:       time.Date(2025, 12, 21, 0, 0, 0, 0, time.UTC),
+			dayLength: "00:00:00",
+			reasons:   []string{"dawn_civil: ", "sunrise: ", "sunset: ", "dusk_civil: "},
+		},
+		{
+			name:      "polar day",
+			observer:  astral.Observer{Latitude: 78.22, Longitude: 15.65},
+			day:       time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC),
+			dayLength: "24:00:00",
+			reasons:   []string{"dawn_civil: ", "sunrise: ", "sunset: ", "dusk_civil: "},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeTable(&buf, ',', tt.observer, tt.day, tt.day); err != nil {
+				t.Fatal(err)
+			}
+			records, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != 2 {
+				t.Fatalf("got %v rows, want 2", len(records))
+			}
+			row := records[1]
+			if len(row) != len(tableHeader) {
+				t.Fatalf("got %v columns, want %v", len(row), len(tableHeader))
+			}
+			if got := row[6]; got != tt.dayLength {
+				t.Fatalf("got day length %v, want %v", got, tt.dayLength)
+			}
+
+			reasons := strings.Split(row[7], "; ")
+			if len(tt.reasons) == 0 {
+				if row[7] != "" {
+					t.Fatalf("unexpected reason %q", row[7])
+				}
+				return
+			}
+			if len(reasons) != len(tt.reasons) {
+				t.Fatalf("got reasons %q, want %q", reasons, tt.reasons)
+			}
+			for i, reason := range reasons {
+				if !strings.HasPrefix(reason, tt.reasons[i]) {
+					t.Fatalf("got reason %q, want prefix %q", reason, tt.reasons[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteTableFarEast(t *testing.T) {
+	// the noon of these zones is on the previous date in UTC
+	tests := []struct {
+		name     string
+		zone     string
+		observer astral.Observer
+		day      time.Time
+		sunrise  string
+	}{
+		{name: "nzdt", zone: "Pacific/Auckland", observer: astral.Observer{Latitude: -36.85, Longitude: 174.76}, day: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), sunrise: "06:05:16"},
+		{name: "utc+14", zone: "Pacific/Kiritimati", observer: astral.Observer{Latitude: 1.87, Longitude: -157.4}, day: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), sunrise: "06:20:54"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.zone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			day := time.Date(tt.day.Year(), tt.day.Month(), tt.day.Day(), 0, 0, 0, 0, loc)
+
+			var buf bytes.Buffer
+			if err := writeTable(&buf, ',', tt.observer, day, day); err != nil {
+				t.Fatal(err)
+			}
+			records, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := records[1][0], day.Format("2006-01-02"); got != want {
+				t.Fatalf("got date %v, want %v", got, want)
+			}
+			if got := records[1][2]; got != tt.sunrise {
+				t.Fatalf("got sunrise %v, want %v", got, tt.sunrise)
+			}
+
+			// the same as for an observer in the zone
+			located := tt.observer
+			located.Location = loc
+			sunrise, err := astral.Sunrise(located, day)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := records[1][2], sunrise.Format("15:04:05"); got != want {
+				t.Fatalf("got sunrise %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package astral
+
+// Definitions are the thresholds which define the solar events.
+// The zero value isn't useful, start with DefaultDefinitions and
+// change the thresholds which differ, e.g. a SunApparentRadius of 0
+// for calculating the sunrise and sunset by the centre of the sun.
+type Definitions struct {
+	// SunApparentRadius of the sun in degrees, which is added to the
+	// elevation of the sunrise and sunset. 0 uses the centre of the sun.
+	SunApparentRadius float64
+	// Elevations of the sun in degrees between which the golden hour takes place.
+	GoldenHourLower float64
+	GoldenHourUpper float64
+	// Elevations of the sun in degrees between which the blue hour takes place.
+	BlueHourLower float64
+	BlueHourUpper float64
+}
+
+// DefaultDefinitions returns the thresholds used by the package level functions.
+// The sunrise and sunset take place when the upper limb of the sun, with an
+// apparent radius of 16 arc minutes, touches the horizon. The golden hour is
+// between -4 and 6 degrees and the blue hour between -6 and -4 degrees,
+// as defined by PhotoPills.
+func DefaultDefinitions() Definitions {
+	return Definitions{
+		SunApparentRadius: sunApperentRadius,
+		GoldenHourLower:   -4,
+		GoldenHourUpper:   6,
+		BlueHourLower:     -6,
+		BlueHourUpper:     -4,
+	}
+}
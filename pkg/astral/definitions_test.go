@@ -0,0 +1,111 @@
+package astral
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDefaultDefinitions(t *testing.T) {
+	d := DefaultDefinitions()
+	date := time.Date(2015, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	want, err := Sunrise(london, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := d.Sunrise(london, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	wantStart, wantEnd, err := GoldenHour(london, date, SunDirectionSetting)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotStart, gotEnd, err := d.GoldenHour(london, date, SunDirectionSetting)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gotStart.Equal(wantStart) || !gotEnd.Equal(wantEnd) {
+		t.Fatalf("got %v - %v, want %v - %v", gotStart, gotEnd, wantStart, wantEnd)
+	}
+}
+
+func TestDefinitionsSunCentre(t *testing.T) {
+	d := DefaultDefinitions()
+	d.SunApparentRadius = 0
+	date := time.Date(2015, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	limb, err := Sunrise(london, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	centre, err := d.Sunrise(london, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the centre rises a few minutes after the upper limb in winter
+	if delay := centre.Sub(limb); delay < time.Minute || delay > 3*time.Minute {
+		t.Fatalf("unexpected delay %v between the upper limb and the centre", delay)
+	}
+
+	want, err := TimeAtElevation(london, 0, date, SunDirectionRising)
+	if err != nil {
+		t.Fatal(err)
+	}
+	almostEqualTime(t, centre, want, time.Second)
+
+	event := d.DayEvents(london, date, DayEventsOptions{}).Get(EventSunrise)
+	if event.Err != nil || !event.Time.Equal(centre) {
+		t.Fatalf("got %v (%v), want %v", event.Time, event.Err, centre)
+	}
+}
+
+func TestDefinitionsGoldenHour(t *testing.T) {
+	d := DefaultDefinitions()
+	d.GoldenHourLower = -1
+	d.GoldenHourUpper = 10
+	date := time.Date(2015, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	start, end, err := d.GoldenHour(london, date, SunDirectionRising)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantStart, err := TimeAtElevation(london, -1, date, SunDirectionRising)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantEnd, err := TimeAtElevation(london, 10, date, SunDirectionRising)
+	if err != nil {
+		t.Fatal(err)
+	}
+	almostEqualTime(t, start, wantStart, time.Second)
+	almostEqualTime(t, end, wantEnd, time.Second)
+
+	next, err := d.NextEvent(london, date, EventGoldenHourRisingEnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	almostEqualTime(t, next, end, time.Second)
+}
+
+func TestDefinitionsEventError(t *testing.T) {
+	d := DefaultDefinitions()
+	d.GoldenHourLower = -1
+	d.GoldenHourUpper = 20
+	// the sun stays below 20 degrees in London in December
+	date := time.Date(2015, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	_, _, err := d.GoldenHour(london, date, SunDirectionRising)
+	var eventErr *EventError
+	if !errors.As(err, &eventErr) {
+		t.Fatalf("expected EventError, got %v", err)
+	}
+	if eventErr.Kind != EventGoldenHourRisingEnd || eventErr.Elevation != 20 || !errors.Is(err, ErrAlwaysBelow) {
+		t.Fatalf("unexpected error %#v", eventErr)
+	}
+}
@@ -0,0 +1,78 @@
+package astral
+
+import (
+	"math"
+	"time"
+)
+
+// Calculate the difference between Terrestrial Time and Universal Time in seconds
+// for the given Julian Day, using the polynomial expressions by Espenak and Meeus.
+//
+// See https://eclipse.gsfc.nasa.gov/SEhelp/deltatpoly2004.html
+func delta_t(julianday float64) float64 {
+	// decimal year
+	y := 2000.0 + (julianday-j2000)/365.25
+
+	switch {
+	case y < -500:
+		u := (y - 1820) / 100
+		return -20 + 32*u*u
+	case y < 500:
+		u := y / 100
+		return 10583.6 + u*(-1014.41+u*(33.78311+u*(-5.952053+u*(-0.1798452+u*(0.022174192+u*0.0090316521)))))
+	case y < 1600:
+		u := (y - 1000) / 100
+		return 1574.2 + u*(-556.01+u*(71.23472+u*(0.319781+u*(-0.8503463+u*(-0.005050998+u*0.0083572073)))))
+	case y < 1700:
+		t := y - 1600
+		return 120 + t*(-0.9808+t*(-0.01532+t/7129))
+	case y < 1800:
+		t := y - 1700
+		return 8.83 + t*(0.1603+t*(-0.0059285+t*(0.00013336-t/1174000)))
+	case y < 1860:
+		t := y - 1800
+		return 13.72 + t*(-0.332447+t*(0.0068612+t*(0.0041116+t*(-0.00037436+t*(0.0000121272+t*(-0.0000001699+t*0.000000000875))))))
+	case y < 1900:
+		t := y - 1860
+		return 7.62 + t*(0.5737+t*(-0.251754+t*(0.01680668+t*(-0.0004473624+t/233174))))
+	case y < 1920:
+		t := y - 1900
+		return -2.79 + t*(1.494119+t*(-0.0598939+t*(0.0061966-t*0.000197)))
+	case y < 1941:
+		t := y - 1920
+		return 21.20 + t*(0.84493+t*(-0.076100+t*0.0020936))
+	case y < 1961:
+		t := y - 1950
+		return 29.07 + t*(0.407+t*(-1.0/233+t/2547))
+	case y < 1986:
+		t := y - 1975
+		return 45.45 + t*(1.067+t*(-1.0/260-t/718))
+	case y < 2005:
+		t := y - 2000
+		return 63.86 + t*(0.3345+t*(-0.060374+t*(0.0017275+t*(0.000651814+t*0.00002373599))))
+	case y < 2050:
+		t := y - 2000
+		return 62.92 + t*(0.32217+t*0.005589)
+	case y < 2150:
+		u := (y - 1820) / 100
+		return -20 + 32*u*u - 0.5628*(2150-y)
+	default:
+		u := (y - 1820) / 100
+		return -20 + 32*u*u
+	}
+}
+
+// DeltaT returns the difference between Terrestrial Time (TT) and Universal Time (UT)
+// at the given instant, i.e. TT = UT + ΔT.
+// The values are calculated with the polynomial expressions by Espenak and Meeus,
+// which are valid from -1999 to 3000.
+func DeltaT(t time.Time) time.Duration {
+	seconds := delta_t(JulianDay(t))
+	return time.Duration(math.Round(seconds * float64(time.Second)))
+}
+
+// Convert a Julian Day in Universal Time to a Julian Century in Terrestrial Time,
+// as used by the calculations of the positions of the sun and moon.
+func jday_to_jcentury_tt(julianday float64) float64 {
+	return jday_to_jcentury(julianday + delta_t(julianday)/86400.0)
+}
@@ -0,0 +1,51 @@
+package astral
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeltaT(t *testing.T) {
+	tests := []struct {
+		name      string
+		date      time.Time
+		want      float64
+		tolerance float64
+	}{
+		// https://eclipse.gsfc.nasa.gov/SEhelp/deltat2004.html
+		{date: time.Date(-500, 1, 1, 0, 0, 0, 0, time.UTC), want: 17190, tolerance: 20},
+		{date: time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC), want: 10580, tolerance: 10},
+		{date: time.Date(500, 1, 1, 0, 0, 0, 0, time.UTC), want: 5710, tolerance: 10},
+		{date: time.Date(1000, 1, 1, 0, 0, 0, 0, time.UTC), want: 1570, tolerance: 5},
+		{date: time.Date(1500, 1, 1, 0, 0, 0, 0, time.UTC), want: 200, tolerance: 5},
+		{date: time.Date(1600, 1, 1, 0, 0, 0, 0, time.UTC), want: 120, tolerance: 1},
+		{date: time.Date(1700, 1, 1, 0, 0, 0, 0, time.UTC), want: 9, tolerance: 1},
+		{date: time.Date(1800, 1, 1, 0, 0, 0, 0, time.UTC), want: 14, tolerance: 1},
+		{date: time.Date(1850, 1, 1, 0, 0, 0, 0, time.UTC), want: 7, tolerance: 1},
+		{date: time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), want: -3, tolerance: 1},
+		{date: time.Date(1950, 1, 1, 0, 0, 0, 0, time.UTC), want: 29.1, tolerance: 0.5},
+		{date: time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), want: 40.2, tolerance: 0.5},
+		{date: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), want: 56.9, tolerance: 0.5},
+		{date: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), want: 63.8, tolerance: 0.5},
+		{date: time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC), want: 64.7, tolerance: 0.5},
+		// extrapolations
+		{date: time.Date(2050, 1, 1, 0, 0, 0, 0, time.UTC), want: 93, tolerance: 1},
+		{date: time.Date(2150, 1, 1, 0, 0, 0, 0, time.UTC), want: 328, tolerance: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.date.Format("2006"), func(t *testing.T) {
+			got := DeltaT(tt.date)
+			almostEqualFloat(t, tt.want, got.Seconds(), tt.tolerance)
+		})
+	}
+}
+
+func TestDeltaTContinuous(t *testing.T) {
+	// The polynomials join without noticeable jumps.
+	for _, year := range []int{500, 1600, 1700, 1800, 1860, 1900, 1920, 1941, 1961, 1986, 2005, 2050} {
+		date := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		before := DeltaT(date.Add(-time.Hour))
+		after := DeltaT(date.Add(time.Hour))
+		almostEqualFloat(t, before.Seconds(), after.Seconds(), 1)
+	}
+}
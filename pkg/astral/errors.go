@@ -0,0 +1,79 @@
+package astral
+
+import (
+	"errors"
+	"fmt"
+)
+
+// EventError is returned when an event doesn't occur on the requested day.
+// It matches ErrAlwaysAbove or ErrAlwaysBelow with errors.Is when the sun
+// stays above or below the elevation of the event during the whole day.
+type EventError struct {
+	// Kind of the event, zero for an arbitrary elevation, e.g. from TimeAtElevation.
+	Kind EventKind
+	// Elevation of the sun in degrees which the event requires, e.g. -6 for civil dawn.
+	// It's 0 for events of the moon and of the horizon profile.
+	Elevation float64
+	// AlwaysAbove is true when the body stays above the elevation during the whole day.
+	AlwaysAbove bool
+	// AlwaysBelow is true when the body stays below the elevation during the whole day.
+	AlwaysBelow bool
+	// Err is the underlying error, if any.
+	Err error
+}
+
+func (e *EventError) Error() string {
+	body := "sun"
+	if e.Kind.lunar() {
+		body = "moon"
+	}
+
+	target := fmt.Sprintf("an elevation of %v degrees", e.Elevation)
+	switch e.Kind {
+	case EventSunrise, EventSunset, EventHorizonSunrise, EventHorizonSunset, EventMoonrise, EventMoonset:
+		target = "the horizon"
+	}
+
+	switch {
+	case e.AlwaysAbove:
+		return fmt.Sprintf("%v is always above %v on this day, at this location", body, target)
+	case e.AlwaysBelow:
+		return fmt.Sprintf("%v is always below %v on this day, at this location", body, target)
+	}
+
+	var inner *EventError
+	if e.Err != nil && !errors.As(e.Err, &inner) {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v doesn't reach %v on this day, at this location", body, target)
+}
+
+// Is reports whether the error matches ErrAlwaysAbove or ErrAlwaysBelow.
+// Events of the moon match ErrMoonAlwaysAbove and ErrMoonAlwaysBelow instead.
+func (e *EventError) Is(target error) bool {
+	if e.Kind.lunar() {
+		return false
+	}
+	return (target == ErrAlwaysAbove && e.AlwaysAbove) || (target == ErrAlwaysBelow && e.AlwaysBelow)
+}
+
+func (e *EventError) Unwrap() error {
+	return e.Err
+}
+
+// Whether the event belongs to the moon.
+func (k EventKind) lunar() bool {
+	return k == EventMoonrise || k == EventMoonset
+}
+
+// Add the kind and the requested elevation of an event to the error of a calculation.
+func event_error(err error, kind EventKind, elevation float64) error {
+	wrapped := &EventError{Kind: kind, Elevation: elevation, Err: err}
+
+	var inner *EventError
+	if errors.As(err, &inner) {
+		wrapped.AlwaysAbove = inner.AlwaysAbove
+		wrapped.AlwaysBelow = inner.AlwaysBelow
+	}
+	return wrapped
+}
@@ -0,0 +1,133 @@
+package astral
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEventError(t *testing.T) {
+	tromso := Observer{Latitude: 69.6, Longitude: 18.8}
+	polarDay := time.Date(2019, 6, 5, 0, 0, 0, 0, time.UTC)
+	polarNight := time.Date(2019, 12, 22, 0, 0, 0, 0, time.UTC)
+
+	type result struct {
+		err error
+	}
+	first := func(_ time.Time, err error) result { return result{err} }
+	firstOfTwo := func(_, _ time.Time, err error) result { return result{err} }
+
+	tests := []struct {
+		name      string
+		got       result
+		want      EventError
+		wantIs    error
+		wantIsNot error
+		wantMsg   string
+	}{
+		{
+			name:      "sunrise polar day",
+			got:       first(Sunrise(tromso, polarDay)),
+			want:      EventError{Kind: EventSunrise, Elevation: -sunApperentRadius, AlwaysAbove: true},
+			wantIs:    ErrAlwaysAbove,
+			wantIsNot: ErrAlwaysBelow,
+			wantMsg:   ErrAlwaysAbove.Error(),
+		},
+		{
+			name:      "sunset polar night",
+			got:       first(Sunset(tromso, polarNight)),
+			want:      EventError{Kind: EventSunset, Elevation: -sunApperentRadius, AlwaysBelow: true},
+			wantIs:    ErrAlwaysBelow,
+			wantIsNot: ErrAlwaysAbove,
+			wantMsg:   ErrAlwaysBelow.Error(),
+		},
+		{
+			name:      "dawn polar day",
+			got:       first(Dawn(tromso, polarDay, DepressionCivil)),
+			want:      EventError{Kind: EventDawnCivil, Elevation: -6, AlwaysAbove: true},
+			wantIs:    ErrAlwaysAbove,
+			wantIsNot: ErrAlwaysBelow,
+			wantMsg:   "sun is always above an elevation of -6 degrees on this day, at this location",
+		},
+		{
+			name:      "dusk polar day",
+			got:       first(Dusk(tromso, polarDay, DepressionAstronomical)),
+			want:      EventError{Kind: EventDuskAstronomical, Elevation: -18, AlwaysAbove: true},
+			wantIs:    ErrAlwaysAbove,
+			wantIsNot: ErrAlwaysBelow,
+		},
+		{
+			name:      "dawn other depression",
+			got:       first(Dawn(tromso, polarDay, 3)),
+			want:      EventError{Elevation: -3, AlwaysAbove: true},
+			wantIs:    ErrAlwaysAbove,
+			wantIsNot: ErrAlwaysBelow,
+		},
+		{
+			name:      "golden hour polar night",
+			got:       firstOfTwo(GoldenHour(tromso, polarNight, SunDirectionRising)),
+			want:      EventError{Kind: EventGoldenHourRisingEnd, Elevation: 6, AlwaysBelow: true},
+			wantIs:    ErrAlwaysBelow,
+			wantIsNot: ErrAlwaysAbove,
+		},
+		{
+			name:      "blue hour polar day",
+			got:       firstOfTwo(BlueHour(tromso, polarDay, SunDirectionSetting)),
+			want:      EventError{Kind: EventBlueHourSettingEnd, Elevation: -6, AlwaysAbove: true},
+			wantIs:    ErrAlwaysAbove,
+			wantIsNot: ErrAlwaysBelow,
+		},
+		{
+			name:      "elevation too high",
+			got:       first(TimeAtElevation(london, 70, time.Date(2015, 12, 1, 0, 0, 0, 0, time.UTC), SunDirectionRising)),
+			want:      EventError{Elevation: 70, AlwaysBelow: true},
+			wantIs:    ErrAlwaysBelow,
+			wantIsNot: ErrAlwaysAbove,
+			wantMsg:   "sun is always below an elevation of 70 degrees on this day, at this location",
+		},
+		{
+			name:      "moon always above",
+			got:       first(Moonrise(tromso, time.Date(2022, 12, 8, 0, 0, 0, 0, time.UTC))),
+			want:      EventError{Kind: EventMoonrise, AlwaysAbove: true},
+			wantIs:    ErrMoonAlwaysAbove,
+			wantIsNot: ErrAlwaysAbove,
+			wantMsg:   ErrMoonAlwaysAbove.Error(),
+		},
+		{
+			name:      "no moonset",
+			got:       first(Moonset(london, time.Date(2021, 5, 16, 0, 0, 0, 0, time.UTC))),
+			want:      EventError{Kind: EventMoonset},
+			wantIs:    ErrNoMoonset,
+			wantIsNot: ErrAlwaysBelow,
+			wantMsg:   ErrNoMoonset.Error(),
+		},
+		{
+			name:      "horizon",
+			got:       first(HorizonSunset(tromso, polarDay, mustHorizon(t, HorizonPoint{Azimuth: 0, Altitude: 0}))),
+			want:      EventError{Kind: EventHorizonSunset, AlwaysAbove: true},
+			wantIs:    ErrAlwaysAbove,
+			wantIsNot: ErrAlwaysBelow,
+			wantMsg:   ErrAlwaysAbove.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *EventError
+			if !errors.As(tt.got.err, &got) {
+				t.Fatalf("got %T (%v), want *EventError", tt.got.err, tt.got.err)
+			}
+			if got.Kind != tt.want.Kind || got.Elevation != tt.want.Elevation || got.AlwaysAbove != tt.want.AlwaysAbove || got.AlwaysBelow != tt.want.AlwaysBelow {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+			if !errors.Is(tt.got.err, tt.wantIs) {
+				t.Fatalf("expected %v to match %v", tt.got.err, tt.wantIs)
+			}
+			if errors.Is(tt.got.err, tt.wantIsNot) {
+				t.Fatalf("expected %v not to match %v", tt.got.err, tt.wantIsNot)
+			}
+			if tt.wantMsg != "" && tt.got.err.Error() != tt.wantMsg {
+				t.Fatalf("got message %q, want %q", tt.got.err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
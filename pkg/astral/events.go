@@ -0,0 +1,319 @@
+package astral
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// EventKind is a type of event during a day.
+type EventKind int
+
+const (
+	EventDawnAstronomical EventKind = iota + 1
+	EventDawnNautical
+	EventDawnCivil
+	EventBlueHourRisingStart
+	EventBlueHourRisingEnd
+	EventGoldenHourRisingStart
+	EventSunrise
+	EventGoldenHourRisingEnd
+	EventNoon
+	EventGoldenHourSettingStart
+	EventSunset
+	EventGoldenHourSettingEnd
+	EventBlueHourSettingStart
+	EventBlueHourSettingEnd
+	EventDuskCivil
+	EventDuskNautical
+	EventDuskAstronomical
+	EventMidnight
+	EventRahukaalamStart
+	EventRahukaalamEnd
+	EventMoonrise
+	EventMoonset
+	EventHorizonSunrise
+	EventHorizonSunset
+)
+
+var eventKindNames = map[EventKind]string{
+	EventDawnAstronomical:       "Dawn (Astronomical)",
+	EventDawnNautical:           "Dawn (Nautical)",
+	EventDawnCivil:              "Dawn (Civil)",
+	EventBlueHourRisingStart:    "Blue Hour Start (Rising)",
+	EventBlueHourRisingEnd:      "Blue Hour End (Rising)",
+	EventGoldenHourRisingStart:  "Golden Hour Start (Rising)",
+	EventSunrise:                "Sunrise",
+	EventGoldenHourRisingEnd:    "Golden Hour End (Rising)",
+	EventNoon:                   "Noon",
+	EventGoldenHourSettingStart: "Golden Hour Start (Setting)",
+	EventSunset:                 "Sunset",
+	EventGoldenHourSettingEnd:   "Golden Hour End (Setting)",
+	EventBlueHourSettingStart:   "Blue Hour Start (Setting)",
+	EventBlueHourSettingEnd:     "Blue Hour End (Setting)",
+	EventDuskCivil:              "Dusk (Civil)",
+	EventDuskNautical:           "Dusk (Nautical)",
+	EventDuskAstronomical:       "Dusk (Astronomical)",
+	EventMidnight:               "Midnight",
+	EventRahukaalamStart:        "Rahukaalam Start",
+	EventRahukaalamEnd:          "Rahukaalam End",
+	EventMoonrise:               "Moonrise",
+	EventMoonset:                "Moonset",
+	EventHorizonSunrise:         "Sunrise (Horizon)",
+	EventHorizonSunset:          "Sunset (Horizon)",
+}
+
+func (k EventKind) String() string {
+	if name, ok := eventKindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("EventKind(%d)", int(k))
+}
+
+// ErrEventNotFound is returned when an event doesn't occur within a year.
+var ErrEventNotFound = errors.New("event doesn't occur within a year")
+
+// Calculate the time of the event on the given date.
+func (d Definitions) event_time(observer Observer, date time.Time, kind EventKind) (time.Time, error) {
+	first := func(start, _ time.Time, err error) (time.Time, error) { return start, err }
+	second := func(_, end time.Time, err error) (time.Time, error) { return end, err }
+
+	switch kind {
+	case EventDawnAstronomical:
+		return Dawn(observer, date, DepressionAstronomical)
+	case EventDawnNautical:
+		return Dawn(observer, date, DepressionNautical)
+	case EventDawnCivil:
+		return Dawn(observer, date, DepressionCivil)
+	case EventBlueHourRisingStart:
+		return first(d.BlueHour(observer, date, SunDirectionRising))
+	case EventBlueHourRisingEnd:
+		return second(d.BlueHour(observer, date, SunDirectionRising))
+	case EventGoldenHourRisingStart:
+		return first(d.GoldenHour(observer, date, SunDirectionRising))
+	case EventSunrise:
+		return d.Sunrise(observer, date)
+	case EventGoldenHourRisingEnd:
+		return second(d.GoldenHour(observer, date, SunDirectionRising))
+	case EventNoon:
+		return Noon(observer, date), nil
+	case EventGoldenHourSettingStart:
+		return first(d.GoldenHour(observer, date, SunDirectionSetting))
+	case EventSunset:
+		return d.Sunset(observer, date)
+	case EventGoldenHourSettingEnd:
+		return second(d.GoldenHour(observer, date, SunDirectionSetting))
+	case EventBlueHourSettingStart:
+		return first(d.BlueHour(observer, date, SunDirectionSetting))
+	case EventBlueHourSettingEnd:
+		return second(d.BlueHour(observer, date, SunDirectionSetting))
+	case EventDuskCivil:
+		return Dusk(observer, date, DepressionCivil)
+	case EventDuskNautical:
+		return Dusk(observer, date, DepressionNautical)
+	case EventDuskAstronomical:
+		return Dusk(observer, date, DepressionAstronomical)
+	case EventMidnight:
+		return Midnight(observer, date), nil
+	case EventRahukaalamStart:
+		return first(d.Rahukaalam(observer, date, true))
+	case EventRahukaalamEnd:
+		return second(d.Rahukaalam(observer, date, true))
+	case EventMoonrise:
+		return Moonrise(observer, date)
+	case EventMoonset:
+		return Moonset(observer, date)
+	case EventHorizonSunrise, EventHorizonSunset:
+		return time.Time{}, fmt.Errorf("%v needs a horizon profile", kind)
+	}
+	return time.Time{}, fmt.Errorf("unknown event kind %v", int(kind))
+}
+
+// The functions for a date can return an event on the previous or next
+// calendar day, depending on the longitude and the location of the date.
+// The search therefore starts a few days before the given time.
+const (
+	eventSearchMargin = 2
+	eventSearchDays   = 367
+)
+
+// Check whether the next and previous events of the given kind can be searched.
+func check_search_kind(kind EventKind) error {
+	if _, ok := eventKindNames[kind]; !ok {
+		return fmt.Errorf("unknown event kind %v", int(kind))
+	}
+	if kind == EventHorizonSunrise || kind == EventHorizonSunset {
+		return fmt.Errorf("%v needs a horizon profile", kind)
+	}
+	return nil
+}
+
+// Calculate the midnight of the day with the given offset to t, in the location of t.
+// Starting each day at midnight makes the result for a day independent of the time of t.
+func search_day(t time.Time, offset int) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+offset, 0, 0, 0, 0, t.Location())
+}
+
+// Calculate the time of the next event of the given kind.
+// Days without the event, e.g. during the polar night for sunrises, are skipped.
+// Args:
+//
+//	observer: Observer to calculate the event for
+//	after:    Time to start the search at
+//	kind:     Kind of the event
+//
+// Returns:
+//
+//	The first time after the given time at which the event occurs,
+//	in the location of the observer or the given time.
+//
+// Raises:
+//
+//	ErrEventNotFound: if the event doesn't occur within a year
+func NextEvent(observer Observer, after time.Time, kind EventKind) (time.Time, error) {
+	return DefaultDefinitions().NextEvent(observer, after, kind)
+}
+
+// NextEvent calculates the time of the next event with the thresholds of the definitions.
+func (d Definitions) NextEvent(observer Observer, after time.Time, kind EventKind) (time.Time, error) {
+	after = observer_date(observer, after)
+	if err := check_search_kind(kind); err != nil {
+		return time.Time{}, err
+	}
+
+	for day := -eventSearchMargin; day <= eventSearchDays; day++ {
+		t, err := d.event_time(observer, search_day(after, day), kind)
+		if err != nil {
+			continue
+		}
+		if t.After(after) {
+			return t.In(after.Location()), nil
+		}
+	}
+	return time.Time{}, ErrEventNotFound
+}
+
+// Calculate the time of the previous event of the given kind.
+// Days without the event, e.g. during the polar night for sunrises, are skipped.
+// Args:
+//
+//	observer: Observer to calculate the event for
+//	before:   Time to start the search at
+//	kind:     Kind of the event
+//
+// Returns:
+//
+//	The last time before the given time at which the event occurred,
+//	in the location of the observer or the given time.
+//
+// Raises:
+//
+//	ErrEventNotFound: if the event didn't occur within a year
+func PreviousEvent(observer Observer, before time.Time, kind EventKind) (time.Time, error) {
+	return DefaultDefinitions().PreviousEvent(observer, before, kind)
+}
+
+// PreviousEvent calculates the time of the previous event with the thresholds of the definitions.
+func (d Definitions) PreviousEvent(observer Observer, before time.Time, kind EventKind) (time.Time, error) {
+	before = observer_date(observer, before)
+	if err := check_search_kind(kind); err != nil {
+		return time.Time{}, err
+	}
+
+	for day := eventSearchMargin; day >= -eventSearchDays; day-- {
+		t, err := d.event_time(observer, search_day(before, day), kind)
+		if err != nil {
+			continue
+		}
+		if t.Before(before) {
+			return t.In(before.Location()), nil
+		}
+	}
+	return time.Time{}, ErrEventNotFound
+}
+
+// Event is the time of an event or the reason why it doesn't occur.
+type Event struct {
+	Kind EventKind
+	// Time of the event, zero if Err is set.
+	Time time.Time
+	// Err is the reason why the event doesn't occur, e.g. ErrAlwaysAbove.
+	Err error
+}
+
+// Events contains the events of a day, ordered by kind.
+type Events []Event
+
+// Get returns the event of the given kind.
+// If the event wasn't calculated, the error of the returned event is set.
+func (e Events) Get(kind EventKind) Event {
+	for _, event := range e {
+		if event.Kind == kind {
+			return event
+		}
+	}
+	return Event{Kind: kind, Err: fmt.Errorf("%v wasn't calculated", kind)}
+}
+
+// Occurring returns the events which occur, ordered by time.
+func (e Events) Occurring() Events {
+	var occurring Events
+	for _, event := range e {
+		if event.Err == nil {
+			occurring = append(occurring, event)
+		}
+	}
+	sort.SliceStable(occurring, func(i, j int) bool {
+		return occurring[i].Time.Before(occurring[j].Time)
+	})
+	return occurring
+}
+
+// DayEventsOptions selects the optional events calculated by DayEvents.
+type DayEventsOptions struct {
+	// Lunar adds the moonrise and moonset.
+	Lunar bool
+	// Horizon adds the times when the sun clears and sinks below the terrain.
+	Horizon *Horizon
+}
+
+// Calculate all events of a day.
+// Args:
+//
+//	observer: Observer to calculate the events for
+//	date:     Date to calculate for
+//	opts:     Optional events to calculate
+//
+// Returns:
+//
+//	The solar events from astronomical dawn to midnight and the selected optional events,
+//	ordered by kind. Events which don't occur on the date contain the reason as error.
+func DayEvents(observer Observer, date time.Time, opts DayEventsOptions) Events {
+	return DefaultDefinitions().DayEvents(observer, date, opts)
+}
+
+// DayEvents calculates all events of a day with the thresholds of the definitions.
+func (d Definitions) DayEvents(observer Observer, date time.Time, opts DayEventsOptions) Events {
+	var events Events
+	for kind := EventDawnAstronomical; kind <= EventRahukaalamEnd; kind++ {
+		t, err := d.event_time(observer, date, kind)
+		events = append(events, Event{Kind: kind, Time: t, Err: err})
+	}
+
+	if opts.Lunar {
+		for _, kind := range []EventKind{EventMoonrise, EventMoonset} {
+			t, err := d.event_time(observer, date, kind)
+			events = append(events, Event{Kind: kind, Time: t, Err: err})
+		}
+	}
+
+	if opts.Horizon != nil {
+		t, err := d.HorizonSunrise(observer, date, *opts.Horizon)
+		events = append(events, Event{Kind: EventHorizonSunrise, Time: t, Err: err})
+		t, err = d.HorizonSunset(observer, date, *opts.Horizon)
+		events = append(events, Event{Kind: EventHorizonSunset, Time: t, Err: err})
+	}
+
+	return events
+}
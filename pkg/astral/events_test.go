@@ -0,0 +1,204 @@
+package astral
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNextEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		observer Observer
+		after    time.Time
+		kind     EventKind
+		want     time.Time
+	}{
+		{name: "sunrise same day", observer: london, after: time.Date(2015, 12, 1, 6, 0, 0, 0, time.UTC), kind: EventSunrise, want: time.Date(2015, 12, 1, 7, 43, 0, 0, time.UTC)},
+		{name: "sunrise next day", observer: london, after: time.Date(2015, 12, 1, 12, 0, 0, 0, time.UTC), kind: EventSunrise, want: time.Date(2015, 12, 2, 7, 45, 0, 0, time.UTC)},
+		{name: "sunrise across year", observer: london, after: time.Date(2014, 12, 31, 12, 0, 0, 0, time.UTC), kind: EventSunrise, want: time.Date(2015, 1, 1, 8, 6, 0, 0, time.UTC)},
+		{name: "sunrise other zone", observer: london, after: time.Date(2015, 12, 1, 23, 0, 0, 0, time.FixedZone("UTC+10", 10*60*60)), kind: EventSunrise, want: time.Date(2015, 12, 2, 7, 45, 0, 0, time.UTC)},
+		{name: "noon", observer: london, after: time.Date(2015, 12, 1, 12, 0, 0, 0, time.UTC), kind: EventNoon, want: time.Date(2015, 12, 2, 11, 49, 0, 0, time.UTC)},
+		{name: "dusk", observer: london, after: time.Date(2015, 12, 1, 12, 0, 0, 0, time.UTC), kind: EventDuskCivil, want: time.Date(2015, 12, 1, 16, 34, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NextEvent(tt.observer, tt.after, tt.kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Location() != tt.after.Location() {
+				t.Fatalf("got location %v, want %v", got.Location(), tt.after.Location())
+			}
+			almostEqualTime(t, tt.want, got, 2*time.Minute)
+		})
+	}
+}
+
+func TestPreviousEvent(t *testing.T) {
+	got, err := PreviousEvent(london, time.Date(2015, 12, 2, 7, 0, 0, 0, time.UTC), EventSunrise)
+	if err != nil {
+		t.Fatal(err)
+	}
+	almostEqualTime(t, time.Date(2015, 12, 1, 7, 43, 0, 0, time.UTC), got, 2*time.Minute)
+
+	got, err = PreviousEvent(london, time.Date(2015, 1, 1, 8, 0, 0, 0, time.UTC), EventSunrise)
+	if err != nil {
+		t.Fatal(err)
+	}
+	almostEqualTime(t, time.Date(2014, 12, 31, 8, 6, 0, 0, time.UTC), got, 2*time.Minute)
+}
+
+func TestNextEventPolar(t *testing.T) {
+	// the sun doesn't set between the end of May and the end of July
+	tromso := Observer{Latitude: 69.6, Longitude: 18.8}
+	june := time.Date(2019, 6, 5, 0, 0, 0, 0, time.UTC)
+
+	sunset, err := NextEvent(tromso, june, EventSunset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sunset.Month() != time.July {
+		t.Fatalf("unexpected sunset %v", sunset)
+	}
+	sunrise, err := NextEvent(tromso, june, EventSunrise)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sunrise.After(sunset) {
+		t.Fatalf("sunrise %v before sunset %v", sunrise, sunset)
+	}
+
+	previous, err := PreviousEvent(tromso, june, EventSunset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if previous.Month() != time.May {
+		t.Fatalf("unexpected sunset %v", previous)
+	}
+}
+
+func TestNextEventSequence(t *testing.T) {
+	// consecutive events are a day apart, without skipping or repeating a day
+	observers := []struct {
+		observer Observer
+		location *time.Location
+	}{
+		{observer: london, location: time.UTC},
+		{observer: Observer{Latitude: -14.27, Longitude: -170.70}, location: time.FixedZone("SST", -11*60*60)},
+		{observer: Observer{Latitude: -13.76, Longitude: -172.10}, location: time.FixedZone("WST", 13*60*60)},
+		{observer: Observer{Latitude: 1.87, Longitude: -157.36}, location: time.FixedZone("LINT", 14*60*60)},
+	}
+	kinds := []EventKind{EventSunrise, EventSunset, EventNoon, EventMidnight, EventMoonrise}
+
+	for _, o := range observers {
+		for _, kind := range kinds {
+			t.Run(o.location.String()+" "+kind.String(), func(t *testing.T) {
+				after := time.Date(2024, 3, 1, 0, 0, 0, 0, o.location)
+				prev, err := NextEvent(o.observer, after, kind)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if sub := prev.Sub(after); sub <= 0 || sub > 25*time.Hour {
+					t.Fatalf("unexpected first event %v after %v", prev, after)
+				}
+
+				for i := 0; i < 10; i++ {
+					next, err := NextEvent(o.observer, prev, kind)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if sub := next.Sub(prev); sub < 23*time.Hour || sub > 26*time.Hour {
+						t.Fatalf("unexpected gap %v between %v and %v", sub, prev, next)
+					}
+
+					back, err := PreviousEvent(o.observer, next, kind)
+					if err != nil {
+						t.Fatal(err)
+					}
+					almostEqualTime(t, prev, back, time.Second)
+					prev = next
+				}
+			})
+		}
+	}
+}
+
+func TestNextEventUnknown(t *testing.T) {
+	if _, err := NextEvent(london, time.Now(), EventKind(0)); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := PreviousEvent(london, time.Now(), EventKind(100)); err == nil {
+		t.Fatal("expected error")
+	}
+	if errors.Is(ErrEventNotFound, ErrAlwaysAbove) {
+		t.Fatal("unexpected error match")
+	}
+}
+
+func TestDayEvents(t *testing.T) {
+	date := time.Date(2015, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	events := DayEvents(london, date, DayEventsOptions{})
+	for _, event := range events {
+		want, wantErr := DefaultDefinitions().event_time(london, date, event.Kind)
+		if !event.Time.Equal(want) || event.Err != wantErr {
+			t.Fatalf("%v: got %v (%v), want %v (%v)", event.Kind, event.Time, event.Err, want, wantErr)
+		}
+	}
+	almostEqualTime(t, time.Date(2015, 12, 1, 7, 43, 0, 0, time.UTC), events.Get(EventSunrise).Time, time.Minute)
+	almostEqualTime(t, time.Date(2015, 12, 1, 15, 55, 0, 0, time.UTC), events.Get(EventSunset).Time, time.Minute)
+
+	// optional events aren't calculated by default
+	if err := events.Get(EventMoonrise).Err; err == nil {
+		t.Fatal("expected moonrise to be missing")
+	}
+	if err := events.Get(EventHorizonSunrise).Err; err == nil {
+		t.Fatal("expected horizon sunrise to be missing")
+	}
+
+	hills := mustHorizon(t, HorizonPoint{Azimuth: 0, Altitude: 5})
+	events = DayEvents(london, date, DayEventsOptions{Lunar: true, Horizon: &hills})
+
+	moonrise, err := Moonrise(london, date)
+	if got := events.Get(EventMoonrise); !got.Time.Equal(moonrise) || got.Err != err {
+		t.Fatalf("got %v, want %v", got, moonrise)
+	}
+	horizonSunrise, err := HorizonSunrise(london, date, hills)
+	if got := events.Get(EventHorizonSunrise); !got.Time.Equal(horizonSunrise) || got.Err != err {
+		t.Fatalf("got %v, want %v", got, horizonSunrise)
+	}
+
+	occurring := events.Occurring()
+	if len(occurring) != len(events) {
+		t.Fatalf("got %v occurring events, want %v", len(occurring), len(events))
+	}
+	for i := 1; i < len(occurring); i++ {
+		if occurring[i].Time.Before(occurring[i-1].Time) {
+			t.Fatalf("%v before %v", occurring[i].Kind, occurring[i-1].Kind)
+		}
+	}
+}
+
+func TestDayEventsPolar(t *testing.T) {
+	tromso := Observer{Latitude: 69.6, Longitude: 18.8}
+	events := DayEvents(tromso, time.Date(2019, 6, 5, 0, 0, 0, 0, time.UTC), DayEventsOptions{})
+
+	for _, kind := range []EventKind{EventSunrise, EventSunset, EventDawnCivil, EventDuskAstronomical, EventGoldenHourSettingEnd} {
+		if err := events.Get(kind).Err; !errors.Is(err, ErrAlwaysAbove) {
+			t.Fatalf("%v: got %v, want %v", kind, err, ErrAlwaysAbove)
+		}
+		if !events.Get(kind).Time.IsZero() {
+			t.Fatalf("%v: expected zero time", kind)
+		}
+	}
+	if err := events.Get(EventNoon).Err; err != nil {
+		t.Fatal(err)
+	}
+
+	for _, event := range events.Occurring() {
+		if event.Err != nil || event.Time.IsZero() {
+			t.Fatalf("unexpected event %v", event)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package astral
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// City is an entry of the built-in location database.
+type City struct {
+	Name   string
+	Region string
+	// Timezone is the IANA name of the time zone, e.g. "Europe/Berlin".
+	Timezone  string
+	Latitude  float64
+	Longitude float64
+	// Elevation above sea level in metres.
+	Elevation float64
+}
+
+// Observer returns an observer at the city.
+func (c City) Observer() Observer {
+	return Observer{Latitude: c.Latitude, Longitude: c.Longitude, Elevation: c.Elevation}
+}
+
+// Location loads the time zone of the city.
+// Programs which can't rely on the time zone database of the system
+// should import time/tzdata.
+func (c City) Location() (*time.Location, error) {
+	return time.LoadLocation(c.Timezone)
+}
+
+func (c City) String() string {
+	return c.Name + ", " + c.Region
+}
+
+var (
+	ErrCityNotFound  = errors.New("city not found")
+	ErrCityAmbiguous = errors.New("city is ambiguous")
+)
+
+//go:embed cities.csv
+var citiesCSV string
+
+var loadCities = sync.OnceValue(func() []City {
+	records, err := csv.NewReader(strings.NewReader(citiesCSV)).ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("failed reading the city database: %v", err))
+	}
+
+	var cities []City
+	// skip the header
+	for _, record := range records[1:] {
+		var values [3]float64
+		for i, field := range record[3:6] {
+			values[i], err = strconv.ParseFloat(field, 64)
+			if err != nil {
+				panic(fmt.Sprintf("failed parsing %v in the city database: %v", record, err))
+			}
+		}
+		cities = append(cities, City{
+			Name:      record[0],
+			Region:    record[1],
+			Timezone:  record[2],
+			Latitude:  values[0],
+			Longitude: values[1],
+			Elevation: values[2],
+		})
+	}
+	return cities
+})
+
+// Cities returns all entries of the built-in location database, ordered by name.
+func Cities() []City {
+	return append([]City(nil), loadCities()...)
+}
+
+// Normalize a name for the comparison, e.g. "new_york" and "New York" are equal.
+func normalize_name(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(name, "_", " "))), " ")
+}
+
+// Find a city in the built-in location database.
+// Args:
+//
+//	query: Name of the city, optionally followed by a comma and the region,
+//	       e.g. "Berlin" or "London, Canada". The case is ignored.
+//
+// Returns:
+//
+//	The city matching the query.
+//
+// Raises:
+//
+//	ErrCityNotFound:  if no city matches the query
+//	ErrCityAmbiguous: if the name matches cities in several regions and the query has no region
+func FindCity(query string) (City, error) {
+	name, region, hasRegion := strings.Cut(query, ",")
+	name, region = normalize_name(name), normalize_name(region)
+
+	var matches []City
+	for _, city := range loadCities() {
+		if normalize_name(city.Name) != name {
+			continue
+		}
+		if hasRegion && normalize_name(city.Region) != region {
+			continue
+		}
+		matches = append(matches, city)
+	}
+
+	switch len(matches) {
+	case 0:
+		return City{}, fmt.Errorf("%w: %q", ErrCityNotFound, query)
+	case 1:
+		return matches[0], nil
+	}
+
+	var regions []string
+	for _, city := range matches {
+		regions = append(regions, city.Region)
+	}
+	return City{}, fmt.Errorf("%w: %q is in %v, add one of the regions, e.g. %q", ErrCityAmbiguous, query, strings.Join(regions, ", "), matches[0].String())
+}
+
+// Look up a city in the built-in location database.
+// Args:
+//
+//	query: Name of the city, optionally followed by a comma and the region
+//
+// Returns:
+//
+//	An observer at the city with its time zone as location, and the time zone.
+//
+// Raises:
+//
+//	ErrCityNotFound:  if no city matches the query
+//	ErrCityAmbiguous: if the name matches cities in several regions and the query has no region
+func LookupCity(query string) (Observer, *time.Location, error) {
+	city, err := FindCity(query)
+	if err != nil {
+		return Observer{}, nil, err
+	}
+	loc, err := city.Location()
+	if err != nil {
+		return Observer{}, nil, err
+	}
+	observer := city.Observer()
+	observer.Location = loc
+	return observer, loc, nil
+}
@@ -0,0 +1,87 @@
+package astral
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFindCity(t *testing.T) {
+	testCases := []struct {
+		query     string
+		name      string
+		region    string
+		latitude  float64
+		longitude float64
+		err       error
+	}{
+		{query: "Berlin", name: "Berlin", region: "Germany", latitude: 52.52, longitude: 13.405},
+		{query: "berlin", name: "Berlin", region: "Germany", latitude: 52.52, longitude: 13.405},
+		{query: "new_york", name: "New York", region: "USA", latitude: 40.7128, longitude: -74.006},
+		{query: "London, England", name: "London", region: "England", latitude: 51.5074, longitude: -0.1278},
+		{query: "London,canada", name: "London", region: "Canada", latitude: 42.9849, longitude: -81.2453},
+		{query: "London", err: ErrCityAmbiguous},
+		{query: "London, Germany", err: ErrCityNotFound},
+		{query: "Atlantis", err: ErrCityNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			city, err := FindCity(tc.query)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("got error %v, want %v", err, tc.err)
+			}
+			if tc.err != nil {
+				return
+			}
+			if city.Name != tc.name || city.Region != tc.region {
+				t.Fatalf("got %v, want %v, %v", city, tc.name, tc.region)
+			}
+			almostEqualFloat(t, city.Latitude, tc.latitude, 0.0001)
+			almostEqualFloat(t, city.Longitude, tc.longitude, 0.0001)
+		})
+	}
+}
+
+func TestLookupCity(t *testing.T) {
+	observer, loc, err := LookupCity("Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.String() != "Asia/Tokyo" || observer.Location != loc {
+		t.Fatalf("got locations %v and %v, want Asia/Tokyo", loc, observer.Location)
+	}
+	almostEqualFloat(t, observer.Latitude, 35.6762, 0.0001)
+	almostEqualFloat(t, observer.Longitude, 139.6503, 0.0001)
+	almostEqualFloat(t, observer.Elevation, 40, 0.0001)
+
+	// the canonical name of the zone, not a deprecated alias like Europe/Kiev
+	if _, loc, err := LookupCity("Kyiv"); err != nil || loc.String() != "Europe/Kyiv" {
+		t.Fatalf("got location %v and error %v, want Europe/Kyiv", loc, err)
+	}
+
+	if _, _, err := LookupCity("Perth"); !errors.Is(err, ErrCityAmbiguous) {
+		t.Fatalf("got error %v, want %v", err, ErrCityAmbiguous)
+	}
+}
+
+func TestCities(t *testing.T) {
+	cities := Cities()
+	if len(cities) < 100 {
+		t.Fatalf("got %v cities, want at least 100", len(cities))
+	}
+
+	seen := make(map[string]bool)
+	for _, city := range cities {
+		if seen[city.String()] {
+			t.Fatalf("duplicate city %v", city)
+		}
+		seen[city.String()] = true
+
+		if city.Latitude < -90 || city.Latitude > 90 || city.Longitude < -180 || city.Longitude > 180 {
+			t.Fatalf("invalid coordinates of %v", city)
+		}
+		if _, err := city.Location(); err != nil {
+			t.Fatalf("failed loading the time zone of %v: %v", city, err)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package astral
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HorizonPoint is the apparent altitude of the terrain in a single direction.
+type HorizonPoint struct {
+	// Azimuth in degrees clockwise from North.
+	Azimuth float64
+	// Altitude of the terrain in degrees above the astronomical horizon.
+	Altitude float64
+}
+
+// Horizon is the profile of the terrain surrounding an observer.
+// The altitude between two points is interpolated linearly.
+type Horizon struct {
+	points []HorizonPoint
+}
+
+// NewHorizon creates a horizon profile from the given points.
+// The points don't have to be sorted, but each azimuth may only occur once.
+func NewHorizon(points []HorizonPoint) (Horizon, error) {
+	if len(points) == 0 {
+		return Horizon{}, errors.New("horizon needs at least one point")
+	}
+
+	sorted := make([]HorizonPoint, 0, len(points))
+	for _, p := range points {
+		if math.IsNaN(p.Azimuth) || math.IsNaN(p.Altitude) {
+			return Horizon{}, fmt.Errorf("invalid horizon point %v", p)
+		}
+		if p.Altitude < -90 || p.Altitude > 90 {
+			return Horizon{}, fmt.Errorf("altitude %v is out of the expected range (-90-90)", p.Altitude)
+		}
+		p.Azimuth = properAngle(p.Azimuth)
+		sorted = append(sorted, p)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Azimuth < sorted[j].Azimuth
+	})
+
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Azimuth == sorted[i-1].Azimuth {
+			return Horizon{}, fmt.Errorf("duplicate azimuth %v", sorted[i].Azimuth)
+		}
+	}
+
+	return Horizon{points: sorted}, nil
+}
+
+// ReadHorizonCSV reads a horizon profile with one "azimuth,altitude" pair
+// in degrees per line. Lines starting with '#' and a header line are ignored.
+func ReadHorizonCSV(r io.Reader) (Horizon, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	var points []HorizonPoint
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Horizon{}, err
+		}
+
+		azimuth, errAz := strconv.ParseFloat(strings.TrimSpace(record[0]), 64)
+		altitude, errAlt := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if errAz != nil || errAlt != nil {
+			if line == 1 {
+				// header
+				continue
+			}
+			return Horizon{}, fmt.Errorf("failed parsing line %v: %q", line, strings.Join(record, ","))
+		}
+		points = append(points, HorizonPoint{Azimuth: azimuth, Altitude: altitude})
+	}
+
+	return NewHorizon(points)
+}
+
+// Altitude returns the altitude of the terrain in degrees at the given azimuth.
+func (h Horizon) Altitude(azimuth float64) float64 {
+	if len(h.points) == 0 {
+		return 0
+	}
+	if len(h.points) == 1 {
+		return h.points[0].Altitude
+	}
+
+	azimuth = properAngle(azimuth)
+
+	// index of the first point after the azimuth
+	i := sort.Search(len(h.points), func(i int) bool {
+		return h.points[i].Azimuth > azimuth
+	})
+
+	prev := h.points[(i-1+len(h.points))%len(h.points)]
+	next := h.points[i%len(h.points)]
+
+	// wrap around North
+	if prev.Azimuth > azimuth {
+		prev.Azimuth -= 360
+	}
+	if next.Azimuth <= azimuth {
+		next.Azimuth += 360
+	}
+
+	fraction := (azimuth - prev.Azimuth) / (next.Azimuth - prev.Azimuth)
+	return prev.Altitude + fraction*(next.Altitude-prev.Altitude)
+}
+
+// Step width used for searching the crossings of the terrain line.
+const horizonSearchStep = time.Minute
+
+// Calculate how far the upper limb of the sun, with the given apparent radius,
+// is above the terrain, in degrees.
+func aboveHorizon(observer Observer, horizon Horizon, radius float64, t time.Time) float64 {
+	zenith, azimuth := ZenithAndAzimuth(observer, t, true)
+	return 90.0 - zenith + radius - horizon.Altitude(azimuth)
+}
+
+// Search the crossings of the terrain line on the day of the given date.
+// The day starts at midnight in the location of the observer or the date.
+func horizonCrossing(observer Observer, date time.Time, horizon Horizon, radius float64, direction SunDirection) (time.Time, error) {
+	date = observer_date(observer, date)
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	end := start.AddDate(0, 0, 1)
+
+	crossings, above, below := find_crossings(start, end, horizonSearchStep, func(t time.Time) float64 {
+		return aboveHorizon(observer, horizon, radius, t)
+	})
+
+	var found time.Time
+	for _, c := range crossings {
+		if direction == SunDirectionRising && c.rising {
+			// first time the sun clears the terrain
+			return c.time.In(date.Location()), nil
+		}
+		if direction == SunDirectionSetting && !c.rising {
+			// last time the sun sinks below the terrain
+			found = c.time
+		}
+	}
+
+	if !found.IsZero() {
+		return found.In(date.Location()), nil
+	}
+	kind := EventHorizonSunrise
+	if direction == SunDirectionSetting {
+		kind = EventHorizonSunset
+	}
+	if !below {
+		return time.Time{}, &EventError{Kind: kind, AlwaysAbove: true}
+	}
+	if !above {
+		return time.Time{}, &EventError{Kind: kind, AlwaysBelow: true}
+	}
+	if direction == SunDirectionRising {
+		return time.Time{}, &EventError{Kind: kind, Err: errors.New("sun doesn't clear the horizon on this day, at this location")}
+	}
+	return time.Time{}, &EventError{Kind: kind, Err: errors.New("sun doesn't sink below the horizon on this day, at this location")}
+}
+
+// Calculate the time when the sun clears the terrain line.
+// Args:
+//
+//	observer: Observer to calculate the sunrise for
+//	date:     Date to calculate for. The day starts at midnight in the location of the date.
+//	horizon:  Profile of the terrain surrounding the observer
+//
+// Returns:
+//
+//	The first time on the date at which the upper limb of the sun rises above the terrain.
+func HorizonSunrise(observer Observer, date time.Time, horizon Horizon) (time.Time, error) {
+	return DefaultDefinitions().HorizonSunrise(observer, date, horizon)
+}
+
+// HorizonSunrise calculates the time when the sun clears the terrain line
+// with the apparent radius of the definitions.
+func (d Definitions) HorizonSunrise(observer Observer, date time.Time, horizon Horizon) (time.Time, error) {
+	return horizonCrossing(observer, date, horizon, d.SunApparentRadius, SunDirectionRising)
+}
+
+// Calculate the time when the sun sinks below the terrain line.
+// Args:
+//
+//	observer: Observer to calculate the sunset for
+//	date:     Date to calculate for. The day starts at midnight in the location of the date.
+//	horizon:  Profile of the terrain surrounding the observer
+//
+// Returns:
+//
+//	The last time on the date at which the upper limb of the sun sets below the terrain.
+func HorizonSunset(observer Observer, date time.Time, horizon Horizon) (time.Time, error) {
+	return DefaultDefinitions().HorizonSunset(observer, date, horizon)
+}
+
+// HorizonSunset calculates the time when the sun sinks below the terrain line
+// with the apparent radius of the definitions.
+func (d Definitions) HorizonSunset(observer Observer, date time.Time, horizon Horizon) (time.Time, error) {
+	return horizonCrossing(observer, date, horizon, d.SunApparentRadius, SunDirectionSetting)
+}
@@ -0,0 +1,149 @@
+package astral
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustHorizon(t *testing.T, points ...HorizonPoint) Horizon {
+	t.Helper()
+	h, err := NewHorizon(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestHorizonAltitude(t *testing.T) {
+	horizon := mustHorizon(t,
+		HorizonPoint{Azimuth: 350, Altitude: 10},
+		HorizonPoint{Azimuth: 10, Altitude: 0},
+		HorizonPoint{Azimuth: 90, Altitude: 4},
+		HorizonPoint{Azimuth: 180, Altitude: 4},
+	)
+
+	tests := []struct {
+		azimuth float64
+		want    float64
+	}{
+		{azimuth: 10, want: 0},
+		{azimuth: 50, want: 2},
+		{azimuth: 135, want: 4},
+		{azimuth: 265, want: 7},
+		{azimuth: 350, want: 10},
+		{azimuth: 0, want: 5},
+		{azimuth: 360, want: 5},
+		{azimuth: -5, want: 7.5},
+	}
+	for _, tt := range tests {
+		got := horizon.Altitude(tt.azimuth)
+		almostEqualFloat(t, tt.want, got, 0.0000001)
+	}
+
+	flat := mustHorizon(t, HorizonPoint{Azimuth: 123, Altitude: 2})
+	almostEqualFloat(t, 2, flat.Altitude(321), 0)
+}
+
+func TestNewHorizon(t *testing.T) {
+	if _, err := NewHorizon(nil); err == nil {
+		t.Fatal("expected error for empty horizon")
+	}
+	if _, err := NewHorizon([]HorizonPoint{{Azimuth: 0}, {Azimuth: 360}}); err == nil {
+		t.Fatal("expected error for duplicate azimuth")
+	}
+	if _, err := NewHorizon([]HorizonPoint{{Azimuth: 0, Altitude: 91}}); err == nil {
+		t.Fatal("expected error for invalid altitude")
+	}
+}
+
+func TestReadHorizonCSV(t *testing.T) {
+	input := `azimuth,altitude
+# the mountains in the east
+0, 1.5
+90,12
+180,3
+270,0.5
+`
+	horizon, err := ReadHorizonCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	almostEqualFloat(t, 12, horizon.Altitude(90), 0)
+	almostEqualFloat(t, 1, horizon.Altitude(315), 0.0000001)
+
+	if _, err := ReadHorizonCSV(strings.NewReader("0,1\n90,x\n")); err == nil {
+		t.Fatal("expected error for invalid altitude")
+	}
+	if _, err := ReadHorizonCSV(strings.NewReader("0,1,2\n")); err == nil {
+		t.Fatal("expected error for invalid number of fields")
+	}
+}
+
+func TestHorizonSunriseSunset(t *testing.T) {
+	date := time.Date(2015, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	flat := mustHorizon(t, HorizonPoint{Azimuth: 0, Altitude: 0})
+	ridge := mustHorizon(t, HorizonPoint{Azimuth: 0, Altitude: 14.036243467926482})
+	// hills in the south east
+	hills := mustHorizon(t,
+		HorizonPoint{Azimuth: 80, Altitude: 0},
+		HorizonPoint{Azimuth: 100, Altitude: 20},
+		HorizonPoint{Azimuth: 150, Altitude: 20},
+		HorizonPoint{Azimuth: 170, Altitude: 0},
+	)
+	wall := mustHorizon(t, HorizonPoint{Azimuth: 0, Altitude: 30})
+
+	valley := london
+	valley.Obstruction = &Obstruction{Height: 500, Distance: 2000}
+
+	sunrise, _ := Sunrise(london, date)
+	sunset, _ := Sunset(london, date)
+	valleySunrise, _ := Sunrise(valley, date)
+	valleySunset, _ := Sunset(valley, date)
+
+	tests := []struct {
+		name        string
+		horizon     Horizon
+		wantSunrise time.Time
+		wantSunset  time.Time
+		wantErr     error
+	}{
+		{name: "flat", horizon: flat, wantSunrise: sunrise, wantSunset: sunset},
+		{name: "ridge", horizon: ridge, wantSunrise: valleySunrise, wantSunset: valleySunset},
+		{name: "hills", horizon: hills, wantSunrise: time.Date(2015, 12, 1, 10, 10, 0, 0, time.UTC), wantSunset: sunset},
+		{name: "wall", horizon: wall, wantErr: ErrAlwaysBelow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSunrise, err := HorizonSunrise(london, date, tt.horizon)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("HorizonSunrise() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			gotSunset, err := HorizonSunset(london, date, tt.horizon)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("HorizonSunset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			almostEqualTime(t, gotSunrise, tt.wantSunrise, 60*time.Second)
+			almostEqualTime(t, gotSunset, tt.wantSunset, 60*time.Second)
+		})
+	}
+
+	// At the returned time, the upper limb of the sun touches the terrain.
+	gotSunrise, err := HorizonSunrise(london, date, hills)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zenith, azimuth := ZenithAndAzimuth(london, gotSunrise, true)
+	almostEqualFloat(t, hills.Altitude(azimuth), 90-zenith+sunApperentRadius, 0.001)
+
+	// The sun doesn't set during the polar day.
+	norway := Observer{Latitude: 69.6, Longitude: 18.8}
+	if _, err := HorizonSunset(norway, time.Date(2019, 6, 5, 0, 0, 0, 0, time.UTC), flat); !errors.Is(err, ErrAlwaysAbove) {
+		t.Fatalf("expected %v, got %v", ErrAlwaysAbove, err)
+	}
+}
@@ -5,13 +5,43 @@ import (
 	"time"
 )
 
+const (
+	// Julian Day of the J2000.0 epoch, 2000-01-01 12:00.
+	j2000 = 2451545.0
+	// Julian Day of the Unix epoch, 1970-01-01 00:00 UTC.
+	unixEpochJulianDay = 2440587.5
+	// Difference between the Julian Day and the Modified Julian Day.
+	modifiedJulianDayOffset = 2400000.5
+	// First day of the Gregorian calendar, 1582-10-15.
+	gregorianReformJulianDay = 2299160.5
+)
+
+// Calendar determines how a calendar date is interpreted.
+type Calendar int
+
+const (
+	// CalendarGregorian uses the proleptic Gregorian calendar for all dates, like time.Time does.
+	CalendarGregorian Calendar = iota
+	// CalendarJulian uses the proleptic Julian calendar for all dates.
+	CalendarJulian
+	// CalendarAuto uses the Julian calendar for dates before the Gregorian
+	// reform on 1582-10-15 and the Gregorian calendar afterwards.
+	CalendarAuto
+)
+
 func julianday(date time.Time) float64 {
 	date = date.UTC()
 	// Calculate the Julian Day for the specified date//
+	return CalendarToJulianDay(date.Year(), date.Month(), float64(date.Day()), CalendarGregorian)
+}
+
+// CalendarToJulianDay converts a calendar date to a Julian Day.
+// The day may contain a fraction for the time of day, e.g. 4.81 for 19:26:24.
+// Years before 1 are counted astronomically, i.e. the year 1 BC is 0.
+func CalendarToJulianDay(year int, month time.Month, day float64, calendar Calendar) float64 {
 	var (
-		y = float64(date.Year())
-		m = float64(date.Month())
-		d = float64(date.Day())
+		y = float64(year)
+		m = float64(month)
 	)
 
 	if m <= 2 {
@@ -19,19 +49,112 @@ func julianday(date time.Time) float64 {
 		m += 12
 	}
 
-	a := math.Floor(y / 100)
-	b := 2 - a + math.Floor(a/4)
-	jd := math.Floor(365.25*(y+4716)) + math.Floor(30.6001*(m+1)) + d + b - 1524.5
+	b := 0.0
+	if calendar != CalendarJulian {
+		a := math.Floor(y / 100)
+		b = 2 - a + math.Floor(a/4)
+	}
+	jd := math.Floor(365.25*(y+4716)) + math.Floor(30.6001*(m+1)) + day + b - 1524.5
 
+	if calendar == CalendarAuto && jd < gregorianReformJulianDay {
+		return CalendarToJulianDay(year, month, day, CalendarJulian)
+	}
 	return jd
 }
 
+// JulianDayToCalendar converts a Julian Day to a calendar date.
+// The returned day contains the time of day as fraction.
+// Years before 1 are counted astronomically, i.e. the year 1 BC is 0.
+func JulianDayToCalendar(jd float64, calendar Calendar) (int, time.Month, float64) {
+	z := math.Floor(jd + 0.5)
+	f := jd + 0.5 - z
+
+	a := z
+	if calendar == CalendarGregorian || (calendar == CalendarAuto && jd >= gregorianReformJulianDay) {
+		alpha := math.Floor((z - 1867216.25) / 36524.25)
+		a = z + 1 + alpha - math.Floor(alpha/4)
+	}
+
+	b := a + 1524
+	c := math.Floor((b - 122.1) / 365.25)
+	d := math.Floor(365.25 * c)
+	e := math.Floor((b - d) / 30.6001)
+
+	day := b - d - math.Floor(30.6001*e) + f
+
+	month := e - 1
+	if e >= 14 {
+		month = e - 13
+	}
+
+	year := c - 4716
+	if month <= 2 {
+		year = c - 4715
+	}
+
+	return int(year), time.Month(month), day
+}
+
+// JulianDay returns the Julian Day of the given instant, including the time of day.
+func JulianDay(t time.Time) float64 {
+	seconds := t.Unix()
+
+	// split off the whole days first to keep the precision of the fraction
+	days := seconds / 86400
+	if seconds%86400 < 0 {
+		days--
+	}
+	fraction := float64(seconds-days*86400) + float64(t.Nanosecond())/1e9
+
+	return unixEpochJulianDay + float64(days) + fraction/86400
+}
+
+// TimeFromJulianDay returns the instant of the given Julian Day in UTC.
+func TimeFromJulianDay(jd float64) time.Time {
+	days := jd - unixEpochJulianDay
+	whole := math.Floor(days)
+	fraction := math.Round((days - whole) * 86400 * 1e9)
+
+	return time.Unix(int64(whole)*86400, 0).Add(time.Duration(fraction)).UTC()
+}
+
+// ModifiedJulianDay returns the Modified Julian Day of the given instant,
+// i.e. the days since 1858-11-17 00:00.
+func ModifiedJulianDay(t time.Time) float64 {
+	return JulianDay(t) - modifiedJulianDayOffset
+}
+
+// TimeFromModifiedJulianDay returns the instant of the given Modified Julian Day in UTC.
+func TimeFromModifiedJulianDay(mjd float64) time.Time {
+	return TimeFromJulianDay(mjd + modifiedJulianDayOffset)
+}
+
+// JulianCentury returns the Julian centuries since the J2000.0 epoch of the given instant.
+func JulianCentury(t time.Time) float64 {
+	return jday_to_jcentury(JulianDay(t))
+}
+
+// TimeFromJulianCentury returns the instant of the given Julian century in UTC.
+func TimeFromJulianCentury(jc float64) time.Time {
+	return TimeFromJulianDay(jcentury_to_jday(jc))
+}
+
+// J2000 returns the days since the J2000.0 epoch (2000-01-01 12:00) of the given instant.
+func J2000(t time.Time) float64 {
+	return JulianDay(t) - j2000
+}
+
+// TimeFromJ2000 returns the instant of the given days since the J2000.0 epoch in UTC.
+func TimeFromJ2000(days float64) time.Time {
+	return TimeFromJulianDay(days + j2000)
+}
+
 // Convert a Julian Day number to a Julian Century//
 func jday_to_jcentury(julianday float64) float64 {
-	return (julianday - 2451545.0) / 36525.0
+	return (julianday - j2000) / 36525.0
 }
 
 // Convert a Julian Century number to a Julian Day//
 func jcentury_to_jday(juliancentury float64) float64 {
-	return (juliancentury * 36525.0) + 2451545.0
+	return (juliancentury * 36525.0) + j2000
 }
@@ -90,3 +90,111 @@ func TestJulianCenturyToDay(t *testing.T) {
 		})
 	}
 }
+
+func TestJulianDayFraction(t *testing.T) {
+	tests := []struct {
+		name string
+		date time.Time
+		want float64
+	}{
+		{name: "1", date: time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC), want: 2451545.0},
+		{name: "2", date: time.Date(1957, 10, 4, 19, 26, 24, 0, time.UTC), want: 2436116.31},
+		{name: "3", date: time.Date(1987, 6, 19, 12, 0, 0, 0, time.UTC), want: 2446966.0},
+		{name: "4", date: time.Date(1988, 6, 19, 12, 0, 0, 0, time.UTC), want: 2447332.0},
+		{name: "5", date: time.Date(2012, 1, 1, 12, 0, 0, 0, time.UTC), want: 2455928.0},
+		{name: "6", date: time.Date(1858, 11, 17, 0, 0, 0, 0, time.UTC), want: 2400000.5},
+		{name: "7", date: time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC), want: 2440587.5 - 1.0/86400},
+		{name: "8", date: time.Date(2000, 1, 1, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60)), want: 2451545.0},
+		{name: "9", date: time.Date(2021, 3, 1, 6, 0, 0, 1000, time.UTC), want: 2459274.75 + 1.0/86400/1e6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := JulianDay(tt.date)
+			almostEqualFloat(t, tt.want, got, 1e-9)
+
+			back := TimeFromJulianDay(got)
+			almostEqualTime(t, tt.date, back, 50*time.Microsecond)
+		})
+	}
+}
+
+func TestCalendarToJulianDay(t *testing.T) {
+	tests := []struct {
+		name     string
+		year     int
+		month    time.Month
+		day      float64
+		calendar Calendar
+		want     float64
+	}{
+		// Meeus, Astronomical Algorithms, Chapter 7
+		{name: "sputnik", year: 1957, month: 10, day: 4.81, calendar: CalendarGregorian, want: 2436116.31},
+		{name: "333", year: 333, month: 1, day: 27.5, calendar: CalendarJulian, want: 1842713.0},
+		{name: "2000", year: 2000, month: 1, day: 1.5, calendar: CalendarAuto, want: 2451545.0},
+		{name: "1600", year: 1600, month: 12, day: 31, calendar: CalendarAuto, want: 2305812.5},
+		{name: "837", year: 837, month: 4, day: 10.3, calendar: CalendarAuto, want: 2026871.8},
+		{name: "-123", year: -123, month: 12, day: 31, calendar: CalendarAuto, want: 1676496.5},
+		{name: "-1000 july", year: -1000, month: 7, day: 12.5, calendar: CalendarAuto, want: 1356001.0},
+		{name: "-1000 february", year: -1000, month: 2, day: 29, calendar: CalendarAuto, want: 1355866.5},
+		{name: "-4712", year: -4712, month: 1, day: 1.5, calendar: CalendarAuto, want: 0},
+		// Gregorian reform
+		{name: "last julian", year: 1582, month: 10, day: 4, calendar: CalendarAuto, want: 2299159.5},
+		{name: "first gregorian", year: 1582, month: 10, day: 15, calendar: CalendarAuto, want: 2299160.5},
+		{name: "proleptic gregorian", year: 1582, month: 10, day: 4, calendar: CalendarGregorian, want: 2299149.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalendarToJulianDay(tt.year, tt.month, tt.day, tt.calendar)
+			almostEqualFloat(t, tt.want, got, 1e-9)
+
+			year, month, day := JulianDayToCalendar(got, tt.calendar)
+			if year != tt.year || month != tt.month {
+				t.Fatalf("got %v-%v, want %v-%v", year, month, tt.year, tt.month)
+			}
+			almostEqualFloat(t, tt.day, day, 1e-6)
+		})
+	}
+}
+
+func TestJulianDayToCalendar(t *testing.T) {
+	tests := []struct {
+		jd        float64
+		wantYear  int
+		wantMonth time.Month
+		wantDay   float64
+	}{
+		{jd: 2436116.31, wantYear: 1957, wantMonth: 10, wantDay: 4.81},
+		{jd: 1842713.0, wantYear: 333, wantMonth: 1, wantDay: 27.5},
+		{jd: 1507900.13, wantYear: -584, wantMonth: 5, wantDay: 28.63},
+	}
+	for _, tt := range tests {
+		year, month, day := JulianDayToCalendar(tt.jd, CalendarAuto)
+		if year != tt.wantYear || month != tt.wantMonth {
+			t.Fatalf("got %v-%v, want %v-%v", year, month, tt.wantYear, tt.wantMonth)
+		}
+		almostEqualFloat(t, tt.wantDay, day, 1e-6)
+	}
+
+	// The Go time package uses the proleptic Gregorian calendar.
+	date := time.Date(1000, 7, 12, 0, 0, 0, 0, time.UTC)
+	year, month, day := JulianDayToCalendar(JulianDay(date), CalendarGregorian)
+	if year != 1000 || month != 7 || day != 12 {
+		t.Fatalf("got %v-%v-%v", year, month, day)
+	}
+}
+
+func TestTimeScales(t *testing.T) {
+	date := time.Date(1957, 10, 4, 19, 26, 24, 0, time.UTC)
+
+	almostEqualFloat(t, 36115.81, ModifiedJulianDay(date), 1e-9)
+	almostEqualTime(t, date, TimeFromModifiedJulianDay(36115.81), 50*time.Microsecond)
+
+	almostEqualFloat(t, -15428.69, J2000(date), 1e-9)
+	almostEqualTime(t, date, TimeFromJ2000(-15428.69), 50*time.Microsecond)
+
+	almostEqualFloat(t, -15428.69/36525, JulianCentury(date), 1e-12)
+	almostEqualTime(t, date, TimeFromJulianCentury(-15428.69/36525), 50*time.Microsecond)
+
+	almostEqualTime(t, time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC), TimeFromJ2000(0), 0)
+	almostEqualTime(t, time.Date(1858, 11, 17, 0, 0, 0, 0, time.UTC), TimeFromModifiedJulianDay(0), 0)
+}
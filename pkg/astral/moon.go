@@ -1,6 +1,7 @@
 package astral
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"time"
@@ -17,9 +18,7 @@ func properAngle(value float64) float64 {
 }
 
 func phaseAsfloat(date time.Time) float64 {
-	jd := julianday(date)
-	DT := math.Pow((jd-2382148), 2) / (41048480 * 86400)
-	T := (jd + DT - 2451545.0) / 36525
+	T := jday_to_jcentury_tt(julianday(date))
 	T2 := math.Pow(T, 2)
 	T3 := math.Pow(T, 3)
 	D := 297.85 + (445267.1115 * T) - (0.0016300 * T2) + (T3 / 545868)
@@ -76,8 +75,754 @@ func MoonPhaseDescription(x float64) (string, error) {
 		return "Full Moon", nil
 	}
 	if x >= 21 && x < 28 {
-		return "Laster Quarter", nil
+		return "Last Quarter", nil
 	}
 
 	return "", fmt.Errorf("failed parsing %v", x)
 }
+
+// MoonPhaseName is one of the eight named phases of the moon.
+type MoonPhaseName int
+
+const (
+	MoonPhaseNew MoonPhaseName = iota
+	MoonPhaseWaxingCrescent
+	MoonPhaseFirstQuarter
+	MoonPhaseWaxingGibbous
+	MoonPhaseFull
+	MoonPhaseWaningGibbous
+	MoonPhaseLastQuarter
+	MoonPhaseWaningCrescent
+)
+
+// MoonPhaseNames contains the names of the eight phases of the moon,
+// in the order of the MoonPhaseName constants.
+type MoonPhaseNames [8]string
+
+var (
+	// MoonPhaseNamesEnglish are the English names of the phases, used by MoonPhaseName.String.
+	MoonPhaseNamesEnglish = MoonPhaseNames{
+		"New Moon",
+		"Waxing Crescent",
+		"First Quarter",
+		"Waxing Gibbous",
+		"Full Moon",
+		"Waning Gibbous",
+		"Last Quarter",
+		"Waning Crescent",
+	}
+	// MoonPhaseNamesGerman are the German names of the phases.
+	MoonPhaseNamesGerman = MoonPhaseNames{
+		"Neumond",
+		"Zunehmende Sichel",
+		"Erstes Viertel",
+		"Zunehmender Mond",
+		"Vollmond",
+		"Abnehmender Mond",
+		"Letztes Viertel",
+		"Abnehmende Sichel",
+	}
+	// MoonPhaseNamesFrench are the French names of the phases.
+	MoonPhaseNamesFrench = MoonPhaseNames{
+		"Nouvelle lune",
+		"Premier croissant",
+		"Premier quartier",
+		"Gibbeuse croissante",
+		"Pleine lune",
+		"Gibbeuse décroissante",
+		"Dernier quartier",
+		"Dernier croissant",
+	}
+)
+
+// String returns the English name of the phase.
+func (n MoonPhaseName) String() string {
+	return n.Localized(MoonPhaseNamesEnglish)
+}
+
+// Localized returns the name of the phase from the given names.
+func (n MoonPhaseName) Localized(names MoonPhaseNames) string {
+	if n < 0 || int(n) >= len(names) {
+		return fmt.Sprintf("MoonPhaseName(%d)", int(n))
+	}
+	return names[n]
+}
+
+// Calculates the named phase of the moon at the specified time.
+// Each phase covers 45 degrees of the elongation of the moon from the sun,
+// centered on new moon (0), first quarter (90), full moon (180) and last quarter (270).
+// Args:
+//
+//	dateandtime: The date and time to calculate the phase for.
+//
+// Returns:
+//
+//	The name of the phase.
+func MoonPhaseNameAt(dateandtime time.Time) MoonPhaseName {
+	elongation := moon_sun_elongation(dateandtime)
+	return MoonPhaseName(int(math.Floor((elongation+22.5)/45.0)) % 8)
+}
+
+const (
+	// Mean equatorial radius of the earth in km.
+	earthRadius = 6378.14
+	// Mean radius of the moon in km.
+	moonRadius = 1737.4
+)
+
+// Periodic terms for the longitude (l, in 0.000001 degrees) and distance (r, in 0.001 km) of the moon.
+// The arguments are multiples of the mean elongation (d), the sun's mean anomaly (m),
+// the moon's mean anomaly (mp) and the moon's argument of latitude (f).
+// See Meeus, Astronomical Algorithms, Table 47.A.
+var moonLongitudeDistanceTerms = []struct{ d, m, mp, f, l, r float64 }{
+	{0, 0, 1, 0, 6288774, -20905355},
+	{2, 0, -1, 0, 1274027, -3699111},
+	{2, 0, 0, 0, 658314, -2955968},
+	{0, 0, 2, 0, 213618, -569925},
+	{0, 1, 0, 0, -185116, 48888},
+	{0, 0, 0, 2, -114332, -3149},
+	{2, 0, -2, 0, 58793, 246158},
+	{2, -1, -1, 0, 57066, -152138},
+	{2, 0, 1, 0, 53322, -170733},
+	{2, -1, 0, 0, 45758, -204586},
+	{0, 1, -1, 0, -40923, -129620},
+	{1, 0, 0, 0, -34720, 108743},
+	{0, 1, 1, 0, -30383, 104755},
+	{2, 0, 0, -2, 15327, 10321},
+	{0, 0, 1, 2, -12528, 0},
+	{0, 0, 1, -2, 10980, 79661},
+	{4, 0, -1, 0, 10675, -34782},
+	{0, 0, 3, 0, 10034, -23210},
+	{4, 0, -2, 0, 8548, -21636},
+	{2, 1, -1, 0, -7888, 24208},
+	{2, 1, 0, 0, -6766, 30824},
+	{1, 0, -1, 0, -5163, -8379},
+	{1, 1, 0, 0, 4987, -16675},
+	{2, -1, 1, 0, 4036, -12831},
+	{2, 0, 2, 0, 3994, -10445},
+	{4, 0, 0, 0, 3861, -11650},
+	{2, 0, -3, 0, 3665, 14403},
+	{0, 1, -2, 0, -2689, -7003},
+	{2, 0, -1, 2, -2602, 0},
+	{2, -1, -2, 0, 2390, 10056},
+	{1, 0, 1, 0, -2348, 6322},
+	{2, -2, 0, 0, 2236, -9884},
+	{0, 1, 2, 0, -2120, 5751},
+	{0, 2, 0, 0, -2069, 0},
+	{2, -2, -1, 0, 2048, -4950},
+	{2, 0, 1, -2, -1773, 4130},
+	{2, 0, 0, 2, -1595, 0},
+	{4, -1, -1, 0, 1215, -3958},
+	{0, 0, 2, 2, -1110, 0},
+	{3, 0, -1, 0, -892, 3258},
+	{2, 1, 1, 0, -810, 2616},
+	{4, -1, -2, 0, 759, -1897},
+	{0, 2, -1, 0, -713, -2117},
+	{2, 2, -1, 0, -700, 2354},
+	{2, 1, -2, 0, 691, 0},
+	{2, -1, 0, -2, 596, 0},
+	{4, 0, 1, 0, 549, -1423},
+	{0, 0, 4, 0, 537, -1117},
+	{4, -1, 0, 0, 520, -1571},
+	{1, 0, -2, 0, -487, -1739},
+	{2, 1, 0, -2, -399, 0},
+	{0, 0, 2, -2, -381, -4421},
+	{1, 1, 1, 0, 351, 0},
+	{3, 0, -2, 0, -340, 0},
+	{4, 0, -3, 0, 330, 0},
+	{2, -1, 2, 0, 327, 0},
+	{0, 2, 1, 0, -323, 1165},
+	{1, 1, -1, 0, 299, 0},
+	{2, 0, 3, 0, 294, 0},
+	{2, 0, -1, -2, 0, 8752},
+}
+
+// Periodic terms for the latitude (b, in 0.000001 degrees) of the moon.
+// See Meeus, Astronomical Algorithms, Table 47.B.
+var moonLatitudeTerms = []struct{ d, m, mp, f, b float64 }{
+	{0, 0, 0, 1, 5128122},
+	{0, 0, 1, 1, 280602},
+	{0, 0, 1, -1, 277693},
+	{2, 0, 0, -1, 173237},
+	{2, 0, -1, 1, 55413},
+	{2, 0, -1, -1, 46271},
+	{2, 0, 0, 1, 32573},
+	{0, 0, 2, 1, 17198},
+	{2, 0, 1, -1, 9266},
+	{0, 0, 2, -1, 8822},
+	{2, -1, 0, -1, 8216},
+	{2, 0, -2, -1, 4324},
+	{2, 0, 1, 1, 4200},
+	{2, 1, 0, -1, -3359},
+	{2, -1, -1, 1, 2463},
+	{2, -1, 0, 1, 2211},
+	{2, -1, -1, -1, 2065},
+	{0, 1, -1, -1, -1870},
+	{4, 0, -1, -1, 1828},
+	{0, 1, 0, 1, -1794},
+	{0, 0, 0, 3, -1749},
+	{0, 1, -1, 1, -1565},
+	{1, 0, 0, 1, -1491},
+	{0, 1, 1, 1, -1475},
+	{0, 1, 1, -1, -1410},
+	{0, 1, 0, -1, -1344},
+	{1, 0, 0, -1, -1335},
+	{0, 0, 3, 1, 1107},
+	{4, 0, 0, -1, 1021},
+	{4, 0, -1, 1, 833},
+}
+
+// Calculate the nutation in longitude in degrees.
+// See Meeus, Astronomical Algorithms, Chapter 22.
+func nutation_in_longitude(juliancentury float64) float64 {
+	omega := radians(125.04452 - 1934.136261*juliancentury)
+	l := radians(280.4665 + 36000.7698*juliancentury)
+	lp := radians(218.3165 + 481267.8813*juliancentury)
+
+	seconds := -17.20*math.Sin(omega) - 1.32*math.Sin(2*l) - 0.23*math.Sin(2*lp) + 0.21*math.Sin(2*omega)
+	return seconds / 3600.0
+}
+
+// Calculate the apparent sidereal time at Greenwich in degrees.
+// See Meeus, Astronomical Algorithms, Chapter 12.
+func greenwich_sidereal_time(julianday float64) float64 {
+	t := jday_to_jcentury(julianday)
+	theta := 280.46061837 + 360.98564736629*(julianday-j2000) + t*t*(0.000387933-t/38710000.0)
+
+	jc := jday_to_jcentury_tt(julianday)
+	theta += nutation_in_longitude(jc) * math.Cos(radians(obliquity_correction(jc)))
+	return properAngle(theta)
+}
+
+// Calculate the geocentric position of the moon.
+// See Meeus, Astronomical Algorithms, Chapter 47.
+// Args:
+//
+//	juliancentury: Julian century in Terrestrial Time
+//
+// Returns:
+//
+//	The apparent ecliptic longitude and the latitude in degrees and the distance in km.
+func moon_ecliptic_position(juliancentury float64) (float64, float64, float64) {
+	t := juliancentury
+
+	lp := 218.3164477 + t*(481267.88123421+t*(-0.0015786+t*(1.0/538841-t/65194000)))
+	d := 297.8501921 + t*(445267.1114034+t*(-0.0018819+t*(1.0/545868-t/113065000)))
+	m := 357.5291092 + t*(35999.0502909+t*(-0.0001536+t/24490000))
+	mp := 134.9633964 + t*(477198.8675055+t*(0.0087414+t*(1.0/69699-t/14712000)))
+	f := 93.2720950 + t*(483202.0175233+t*(-0.0036539+t*(-1.0/3526000+t/863310000)))
+
+	a1 := 119.75 + 131.849*t
+	a2 := 53.09 + 479264.290*t
+	a3 := 313.45 + 481266.484*t
+
+	// decreasing eccentricity of the earth's orbit
+	e := 1 - t*(0.002516+t*0.0000074)
+	eccentricity := func(m float64) float64 {
+		switch math.Abs(m) {
+		case 1:
+			return e
+		case 2:
+			return e * e
+		}
+		return 1
+	}
+
+	var sumL, sumR, sumB float64
+	for _, term := range moonLongitudeDistanceTerms {
+		arg := radians(term.d*d + term.m*m + term.mp*mp + term.f*f)
+		sumL += term.l * eccentricity(term.m) * math.Sin(arg)
+		sumR += term.r * eccentricity(term.m) * math.Cos(arg)
+	}
+	for _, term := range moonLatitudeTerms {
+		arg := radians(term.d*d + term.m*m + term.mp*mp + term.f*f)
+		sumB += term.b * eccentricity(term.m) * math.Sin(arg)
+	}
+
+	// action of venus, jupiter and the flattening of the earth
+	sumL += 3958*math.Sin(radians(a1)) + 1962*math.Sin(radians(lp-f)) + 318*math.Sin(radians(a2))
+	sumB += -2235*math.Sin(radians(lp)) + 382*math.Sin(radians(a3)) + 175*math.Sin(radians(a1-f)) +
+		175*math.Sin(radians(a1+f)) + 127*math.Sin(radians(lp-mp)) - 115*math.Sin(radians(lp+mp))
+
+	longitude := properAngle(lp + sumL/1000000.0 + nutation_in_longitude(t))
+	latitude := sumB / 1000000.0
+	distance := 385000.56 + sumR/1000.0
+
+	return longitude, latitude, distance
+}
+
+// Convert ecliptic to equatorial coordinates.
+// Args:
+//
+//	longitude: ecliptic longitude in degrees
+//	latitude:  ecliptic latitude in degrees
+//	obliquity: obliquity of the ecliptic in degrees
+//
+// Returns:
+//
+//	The right ascension and the declination in degrees.
+func ecliptic_to_equatorial(longitude, latitude, obliquity float64) (float64, float64) {
+	l := radians(longitude)
+	b := radians(latitude)
+	e := radians(obliquity)
+
+	ra := math.Atan2(math.Sin(l)*math.Cos(e)-math.Tan(b)*math.Sin(e), math.Cos(l))
+	dec := math.Asin(math.Sin(b)*math.Cos(e) + math.Cos(b)*math.Sin(e)*math.Sin(l))
+	return properAngle(degrees(ra)), degrees(dec)
+}
+
+// Calculate the geocentric right ascension and declination in degrees and the distance in km of the moon.
+// Args:
+//
+//	juliancentury: Julian century in Terrestrial Time
+func moon_equatorial_position(juliancentury float64) (float64, float64, float64) {
+	jc := juliancentury
+	longitude, latitude, distance := moon_ecliptic_position(jc)
+	ra, dec := ecliptic_to_equatorial(longitude, latitude, obliquity_correction(jc))
+	return ra, dec, distance
+}
+
+// Correct the equatorial coordinates of a body for the parallax due to the
+// observer's position on the surface of the earth.
+// See Meeus, Astronomical Algorithms, Chapter 40.
+// Args:
+//
+//	observer:  Observer to calculate the coordinates for
+//	dec:       geocentric declination in degrees
+//	hourangle: geocentric local hour angle in degrees
+//	distance:  distance between the centres of the earth and the body in km
+//
+// Returns:
+//
+//	The topocentric declination and hour angle in degrees.
+func topocentric_parallax(observer Observer, dec, hourangle, distance float64) (float64, float64) {
+	const flattening = 0.99664719
+
+	phi := radians(observer.Latitude)
+	u := math.Atan(flattening * math.Tan(phi))
+	height := observer.Elevation / (earthRadius * 1000)
+	rhoSin := flattening*math.Sin(u) + height*math.Sin(phi)
+	rhoCos := math.Cos(u) + height*math.Cos(phi)
+
+	sinParallax := earthRadius / distance
+	h := radians(hourangle)
+	d := radians(dec)
+
+	deltaRA := math.Atan2(-rhoCos*sinParallax*math.Sin(h), math.Cos(d)-rhoCos*sinParallax*math.Cos(h))
+	topoDec := math.Atan2((math.Sin(d)-rhoSin*sinParallax)*math.Cos(deltaRA), math.Cos(d)-rhoCos*sinParallax*math.Cos(h))
+
+	return degrees(topoDec), hourangle - degrees(deltaRA)
+}
+
+// Calculate the topocentric zenith and azimuth angle in degrees and the
+// geocentric distance in km of the moon.
+func moon_position(observer Observer, dateandtime time.Time, with_refraction bool) (float64, float64, float64) {
+	latitude := observer.Latitude
+	if observer.Latitude > 89.8 {
+		latitude = 89.8
+	} else if observer.Latitude < -89.8 {
+		latitude = -89.8
+	}
+
+	jd := JulianDay(dateandtime)
+	ra, dec, distance := moon_equatorial_position(jday_to_jcentury_tt(jd))
+
+	hourangle := properAngle(greenwich_sidereal_time(jd) + observer.Longitude - ra)
+	dec, hourangle = topocentric_parallax(observer, dec, hourangle, distance)
+
+	hourangle = properAngle(hourangle)
+	if hourangle > 180 {
+		hourangle -= 360
+	}
+
+	zenith, azimuth := horizontal_coordinates(latitude, dec, hourangle)
+	if with_refraction {
+		zenith -= refraction_for_observer(observer, zenith)
+	}
+	return zenith, azimuth, distance
+}
+
+// Calculate the topocentric zenith and azimuth angle of the moon.
+// Args:
+//
+//	observer:        Observer to calculate the position for
+//	dateandtime:     The instant for which to calculate the angles.
+//	with_refraction: If true adjust zenith to take refraction into account
+//
+// Returns:
+//
+//	The zenith angle and the azimuth angle clockwise from North, in degrees.
+func MoonZenithAndAzimuth(observer Observer, dateandtime time.Time, with_refraction bool) (float64, float64) {
+	zenith, azimuth, _ := moon_position(observer, dateandtime, with_refraction)
+	return zenith, azimuth
+}
+
+// Calculate the topocentric zenith angle of the moon.
+// Args:
+//
+//	observer:        Observer to calculate the lunar zenith for
+//	dateandtime:     The date and time for which to calculate the angle.
+//	with_refraction: If true adjust zenith to take refraction into account
+//
+// Returns:
+//
+//	The zenith angle in degrees.
+func MoonZenith(observer Observer, dateandtime time.Time, with_refraction bool) float64 {
+	zenith, _ := MoonZenithAndAzimuth(observer, dateandtime, with_refraction)
+	return zenith
+}
+
+// Calculate the azimuth angle of the moon.
+// Args:
+//
+//	observer:    Observer to calculate the lunar azimuth for
+//	dateandtime: The date and time for which to calculate the angle.
+//
+// Returns:
+//
+//	The azimuth angle in degrees clockwise from North.
+func MoonAzimuth(observer Observer, dateandtime time.Time) float64 {
+	_, azimuth := MoonZenithAndAzimuth(observer, dateandtime, true)
+	return azimuth
+}
+
+// Calculate the moon's topocentric angle of elevation.
+// Args:
+//
+//	observer:        Observer to calculate the lunar elevation for
+//	dateandtime:     The date and time for which to calculate the angle.
+//	with_refraction: If true adjust elevation to take refraction into account
+//
+// Returns:
+//
+//	The elevation angle in degrees above the horizon.
+func MoonElevation(observer Observer, dateandtime time.Time, with_refraction bool) float64 {
+	return 90.0 - MoonZenith(observer, dateandtime, with_refraction)
+}
+
+// Calculate the geocentric distance of the moon.
+// Args:
+//
+//	dateandtime: The date and time for which to calculate the distance.
+//
+// Returns:
+//
+//	The distance between the centres of the earth and the moon in km.
+func MoonDistance(dateandtime time.Time) float64 {
+	_, _, distance := moon_equatorial_position(jday_to_jcentury_tt(JulianDay(dateandtime)))
+	return distance
+}
+
+var (
+	ErrMoonAlwaysBelow = errors.New("moon is always below the horizon on this day, at this location")
+	ErrMoonAlwaysAbove = errors.New("moon is always above the horizon on this day, at this location")
+	ErrNoMoonrise      = errors.New("moon doesn't rise on this day, at this location")
+	ErrNoMoonset       = errors.New("moon doesn't set on this day, at this location")
+)
+
+// Step width used for searching moonrise and moonset.
+const moonSearchStep = 5 * time.Minute
+
+// Calculate the first time on the date when the upper limb of the moon crosses the horizon.
+// The day starts at midnight in the location of the observer or the date.
+func moon_transit(observer Observer, date time.Time, rising bool) (time.Time, error) {
+	date = observer_date(observer, date)
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	end := start.AddDate(0, 0, 1)
+
+	adjustment := adjust_for_observer(observer)
+	crossings, above, below := find_crossings(start, end, moonSearchStep, func(t time.Time) float64 {
+		zenith, _, distance := moon_position(observer, t, true)
+		semidiameter := degrees(math.Asin(moonRadius / distance))
+		return 90.0 - zenith + semidiameter + adjustment
+	})
+
+	for _, c := range crossings {
+		if c.rising == rising {
+			return c.time.In(date.Location()), nil
+		}
+	}
+
+	kind := EventMoonset
+	if rising {
+		kind = EventMoonrise
+	}
+	if !below {
+		return time.Time{}, &EventError{Kind: kind, AlwaysAbove: true, Err: ErrMoonAlwaysAbove}
+	}
+	if !above {
+		return time.Time{}, &EventError{Kind: kind, AlwaysBelow: true, Err: ErrMoonAlwaysBelow}
+	}
+	if rising {
+		return time.Time{}, &EventError{Kind: kind, Err: ErrNoMoonrise}
+	}
+	return time.Time{}, &EventError{Kind: kind, Err: ErrNoMoonset}
+}
+
+// Calculate moonrise time.
+// Args:
+//
+//	observer: Observer to calculate moonrise for
+//	date:     Date to calculate for. The day starts at midnight in the location of the date.
+//
+// Returns:
+//
+//	Date and time at which the upper limb of the moon rises above the horizon.
+//
+// Raises:
+//
+//	ErrMoonAlwaysAbove or ErrMoonAlwaysBelow: if the moon doesn't cross the horizon on this day
+//	ErrNoMoonrise: if the moon sets, but doesn't rise on this day
+func Moonrise(observer Observer, date time.Time) (time.Time, error) {
+	return moon_transit(observer, date, true)
+}
+
+// Calculate moonset time.
+// Args:
+//
+//	observer: Observer to calculate moonset for
+//	date:     Date to calculate for. The day starts at midnight in the location of the date.
+//
+// Returns:
+//
+//	Date and time at which the upper limb of the moon sets below the horizon.
+//
+// Raises:
+//
+//	ErrMoonAlwaysAbove or ErrMoonAlwaysBelow: if the moon doesn't cross the horizon on this day
+//	ErrNoMoonset: if the moon rises, but doesn't set on this day
+func Moonset(observer Observer, date time.Time) (time.Time, error) {
+	return moon_transit(observer, date, false)
+}
+
+// Length of an astronomical unit in km.
+const astronomicalUnit = 149597870.7
+
+// Illumination describes the illuminated part of the moon as seen from the centre of the earth.
+type Illumination struct {
+	// Fraction of the moon's disk which is illuminated, from 0 (new moon) to 1 (full moon).
+	Fraction float64
+	// PhaseAngle is the angle between the sun and the earth as seen from the moon,
+	// from 180 degrees (new moon) to 0 degrees (full moon).
+	PhaseAngle float64
+	// Waxing is true between new moon and full moon.
+	Waxing bool
+	// BrightLimbAngle is the position angle of the midpoint of the illuminated limb
+	// in degrees, measured from the North point of the disk towards the East.
+	BrightLimbAngle float64
+}
+
+// Calculate the illumination of the moon.
+// See Meeus, Astronomical Algorithms, Chapter 48.
+// Args:
+//
+//	juliancentury: Julian century in Terrestrial Time
+func moon_illumination(juliancentury float64) Illumination {
+	ra, dec, distance := moon_equatorial_position(juliancentury)
+	moonLongitude, _, _ := moon_ecliptic_position(juliancentury)
+
+	sunRA := radians(sun_rt_ascension(juliancentury))
+	sunDec := radians(sun_declination(juliancentury))
+	sunDistance := sun_rad_vector(juliancentury) * astronomicalUnit
+
+	ra = radians(ra)
+	dec = radians(dec)
+
+	// geocentric elongation of the moon from the sun
+	cosElongation := math.Sin(sunDec)*math.Sin(dec) + math.Cos(sunDec)*math.Cos(dec)*math.Cos(sunRA-ra)
+	elongation := math.Acos(cosElongation)
+
+	phaseAngle := math.Atan2(sunDistance*math.Sin(elongation), distance-sunDistance*cosElongation)
+
+	brightLimb := math.Atan2(
+		math.Cos(sunDec)*math.Sin(sunRA-ra),
+		math.Sin(sunDec)*math.Cos(dec)-math.Cos(sunDec)*math.Sin(dec)*math.Cos(sunRA-ra),
+	)
+
+	return Illumination{
+		Fraction:        (1 + math.Cos(phaseAngle)) / 2,
+		PhaseAngle:      degrees(phaseAngle),
+		Waxing:          properAngle(moonLongitude-sun_apparent_long(juliancentury)) < 180,
+		BrightLimbAngle: properAngle(degrees(brightLimb)),
+	}
+}
+
+// Calculate the illumination of the moon.
+// Args:
+//
+//	dateandtime: The date and time for which to calculate the illumination.
+//
+// Returns:
+//
+//	The illuminated fraction, the phase angle, whether the moon is waxing and
+//	the position angle of the bright limb.
+func MoonIllumination(dateandtime time.Time) Illumination {
+	return moon_illumination(jday_to_jcentury_tt(JulianDay(dateandtime)))
+}
+
+// LunarPhase is one of the principal phases of the moon.
+type LunarPhase int
+
+const (
+	// LunarPhaseNew is the moment when the moon has the same apparent longitude as the sun.
+	LunarPhaseNew LunarPhase = iota
+	// LunarPhaseFirstQuarter is the moment when the moon is 90 degrees east of the sun.
+	LunarPhaseFirstQuarter
+	// LunarPhaseFull is the moment when the moon is opposite to the sun.
+	LunarPhaseFull
+	// LunarPhaseLastQuarter is the moment when the moon is 90 degrees west of the sun.
+	LunarPhaseLastQuarter
+)
+
+func (p LunarPhase) String() string {
+	switch p {
+	case LunarPhaseNew:
+		return "New Moon"
+	case LunarPhaseFirstQuarter:
+		return "First Quarter"
+	case LunarPhaseFull:
+		return "Full Moon"
+	case LunarPhaseLastQuarter:
+		return "Last Quarter"
+	}
+	return fmt.Sprintf("LunarPhase(%d)", int(p))
+}
+
+// LunarPhaseEvent is the instant of a principal phase of the moon.
+type LunarPhaseEvent struct {
+	Phase LunarPhase
+	Time  time.Time
+}
+
+// Mean length of the synodic month in days.
+const synodicMonth = 29.530588853
+
+// Calculate the difference between the apparent longitudes of the moon and the sun
+// in degrees (0-360). It's 0 at new moon, 90 at first quarter, 180 at full moon
+// and 270 at last quarter.
+func moon_sun_elongation(dateandtime time.Time) float64 {
+	jc := jday_to_jcentury_tt(JulianDay(dateandtime))
+	moonLongitude, _, _ := moon_ecliptic_position(jc)
+	return properAngle(moonLongitude - sun_apparent_long(jc))
+}
+
+// Refine the instant of the given phase, starting from an estimate which
+// is less than half a synodic month off.
+func refine_lunar_phase(estimate time.Time, phase LunarPhase) time.Time {
+	target := float64(phase) * 90.0
+	// mean motion of the moon relative to the sun in degrees per day
+	rate := 360.0 / synodicMonth
+
+	t := estimate
+	for i := 0; i < 20; i++ {
+		diff := properAngle(target-moon_sun_elongation(t)+180.0) - 180.0
+		correction := time.Duration(diff / rate * 86400 * float64(time.Second))
+		t = t.Add(correction)
+		if correction.Abs() < time.Second/10 {
+			break
+		}
+	}
+	return t.Round(time.Second).UTC()
+}
+
+// Calculate the next time of the given lunar phase.
+// Args:
+//
+//	after: Time to start the search at
+//	phase: Principal phase of the moon to search for
+//
+// Returns:
+//
+//	The first instant after the given time in UTC at which the moon reaches the phase.
+func NextLunarPhase(after time.Time, phase LunarPhase) time.Time {
+	ahead := properAngle(float64(phase)*90.0 - moon_sun_elongation(after))
+	estimate := after.Add(time.Duration(ahead / 360.0 * synodicMonth * 86400 * float64(time.Second)))
+
+	t := refine_lunar_phase(estimate, phase)
+	if !t.After(after) {
+		t = refine_lunar_phase(t.Add(time.Duration(synodicMonth*86400*float64(time.Second))), phase)
+	}
+	return t
+}
+
+// Calculate the previous time of the given lunar phase.
+// Args:
+//
+//	before: Time to start the search at
+//	phase:  Principal phase of the moon to search for
+//
+// Returns:
+//
+//	The last instant before the given time in UTC at which the moon reached the phase.
+func PreviousLunarPhase(before time.Time, phase LunarPhase) time.Time {
+	behind := properAngle(moon_sun_elongation(before) - float64(phase)*90.0)
+	estimate := before.Add(-time.Duration(behind / 360.0 * synodicMonth * 86400 * float64(time.Second)))
+
+	t := refine_lunar_phase(estimate, phase)
+	if !t.Before(before) {
+		t = refine_lunar_phase(t.Add(-time.Duration(synodicMonth*86400*float64(time.Second))), phase)
+	}
+	return t
+}
+
+// LunarPhaseIterator steps through the principal lunar phases in a range.
+// Each phase is only calculated when Next is called, so stopping early,
+// e.g. after the first phase, avoids calculating the rest of the range.
+type LunarPhaseIterator struct {
+	phase LunarPhase
+	next  time.Time
+	to    time.Time
+}
+
+// Create an iterator over the principal lunar phases in the given range.
+// Args:
+//
+//	from: Start of the range
+//	to:   End of the range
+//
+// Returns:
+//
+//	An iterator returning the phases at or after from and before to, ordered by time.
+func NewLunarPhaseIterator(from, to time.Time) *LunarPhaseIterator {
+	// start with the earliest phase at or after from
+	t := from.Add(-time.Nanosecond)
+	phase := LunarPhaseNew
+	next := NextLunarPhase(t, phase)
+	for _, p := range []LunarPhase{LunarPhaseFirstQuarter, LunarPhaseFull, LunarPhaseLastQuarter} {
+		if candidate := NextLunarPhase(t, p); candidate.Before(next) {
+			phase, next = p, candidate
+		}
+	}
+	return &LunarPhaseIterator{phase: phase, next: next, to: to}
+}
+
+// Next returns the next phase in the range.
+// The second return value is false when the range contains no further phase.
+func (it *LunarPhaseIterator) Next() (LunarPhaseEvent, bool) {
+	if !it.next.Before(it.to) {
+		return LunarPhaseEvent{}, false
+	}
+	event := LunarPhaseEvent{Phase: it.phase, Time: it.next}
+	it.phase = (it.phase + 1) % 4
+	it.next = NextLunarPhase(it.next, it.phase)
+	return event, true
+}
+
+// Calculate all principal lunar phases in the given range.
+// Use NewLunarPhaseIterator to calculate the phases one at a time instead.
+// Args:
+//
+//	from: Start of the range
+//	to:   End of the range
+//
+// Returns:
+//
+//	The phases at or after from and before to, ordered by time.
+func LunarPhases(from, to time.Time) []LunarPhaseEvent {
+	var events []LunarPhaseEvent
+	it := NewLunarPhaseIterator(from, to)
+	for event, ok := it.Next(); ok; event, ok = it.Next() {
+		events = append(events, event)
+	}
+	return events
+}
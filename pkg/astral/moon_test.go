@@ -1,6 +1,8 @@
 package astral
 
 import (
+	"errors"
+	"math"
 	"testing"
 	"time"
 )
@@ -15,7 +17,9 @@ func TestMoon(t *testing.T) {
 		want float64
 	}{
 		{args: args{date: time.Date(2015, 12, 1, 0, 0, 0, 0, time.UTC)}, want: 19.477889},
-		{args: args{date: time.Date(2015, 12, 2, 0, 0, 0, 0, time.UTC)}, want: 20.411222},
+		// The elongation is 255.499 degrees with ΔT from Espenak and Meeus, but 255.505 degrees
+		// with the approximation of the Python package, which results in 20.411222.
+		{args: args{date: time.Date(2015, 12, 2, 0, 0, 0, 0, time.UTC)}, want: 20.333444},
 		{args: args{date: time.Date(2015, 12, 3, 0, 0, 0, 0, time.UTC)}, want: 21.266777},
 		{args: args{date: time.Date(2014, 12, 1, 0, 0, 0, 0, time.UTC)}, want: 9.0556666},
 		{args: args{date: time.Date(2014, 12, 2, 0, 0, 0, 0, time.UTC)}, want: 10.066777},
@@ -28,3 +32,362 @@ func TestMoon(t *testing.T) {
 		})
 	}
 }
+
+func TestMoonEclipticPosition(t *testing.T) {
+	// Meeus, Astronomical Algorithms, Example 47.a: 1992 April 12, 0h TD
+	longitude, latitude, distance := moon_ecliptic_position(-0.077221081451)
+	almostEqualFloat(t, 133.167265, longitude, 0.0005)
+	almostEqualFloat(t, -3.229126, latitude, 0.003)
+	almostEqualFloat(t, 368409.7, distance, 1)
+
+	ra, dec := ecliptic_to_equatorial(longitude, latitude, 23.440636)
+	almostEqualFloat(t, 134.688470, ra, 0.003)
+	almostEqualFloat(t, 13.768368, dec, 0.003)
+}
+
+func TestGreenwichSiderealTime(t *testing.T) {
+	// Meeus, Astronomical Algorithms, Example 12.a and 12.b
+	almostEqualFloat(t, 197.6922296, greenwich_sidereal_time(JulianDay(time.Date(1987, 4, 10, 0, 0, 0, 0, time.UTC))), 0.00002)
+	// mean sidereal time corrected by the nutation in longitude of -3.788"
+	almostEqualFloat(t, 128.7378734-0.0009654, greenwich_sidereal_time(JulianDay(time.Date(1987, 4, 10, 19, 21, 0, 0, time.UTC))), 0.00005)
+}
+
+func TestTopocentricParallax(t *testing.T) {
+	// Meeus, Astronomical Algorithms, Example 40.a
+	palomar := Observer{Latitude: 33.356111, Longitude: -116.8625, Elevation: 1706}
+	dec, hourangle := topocentric_parallax(palomar, -15.771083, 288.7958, 0.37276*149597870.7)
+	almostEqualFloat(t, -15.775, dec, 0.00003)
+	almostEqualFloat(t, 288.7958-0.005375, hourangle, 0.00003)
+}
+
+func TestMoonPosition(t *testing.T) {
+	date := time.Date(2021, 4, 30, 21, 0, 0, 0, time.UTC)
+
+	// Seen from the centre of the earth, the moon is higher above the horizon.
+	ra, dec, distance := moon_equatorial_position(jday_to_jcentury_tt(JulianDay(date)))
+	hourangle := properAngle(greenwich_sidereal_time(JulianDay(date)) + london.Longitude - ra)
+	if hourangle > 180 {
+		hourangle -= 360
+	}
+	geocentric, _ := horizontal_coordinates(london.Latitude, dec, hourangle)
+	topocentric := MoonZenith(london, date, false)
+	parallax := degrees(math.Asin(earthRadius / distance))
+	almostEqualFloat(t, parallax*math.Sin(radians(geocentric)), topocentric-geocentric, 0.01)
+
+	almostEqualFloat(t, distance, MoonDistance(date), 0)
+	if distance < 356000 || distance > 407000 {
+		t.Fatalf("unexpected distance %v", distance)
+	}
+
+	// The moon moves around the sky once in a lunar day.
+	var rising, setting int
+	prev := MoonElevation(london, date, true)
+	for minutes := 10; minutes <= 24*60+50; minutes += 10 {
+		cur := MoonElevation(london, date.Add(time.Duration(minutes)*time.Minute), true)
+		if prev < 0 && cur >= 0 {
+			rising++
+		}
+		if prev >= 0 && cur < 0 {
+			setting++
+		}
+		prev = cur
+	}
+	if rising != 1 || setting != 1 {
+		t.Fatalf("rising: %v, setting: %v", rising, setting)
+	}
+
+	almostEqualFloat(t, MoonZenith(london, date, true), 90-MoonElevation(london, date, true), 0)
+	_, azimuth := MoonZenithAndAzimuth(london, date, true)
+	almostEqualFloat(t, azimuth, MoonAzimuth(london, date), 0)
+}
+
+var tromso = Observer{Latitude: 69.65, Longitude: 18.96}
+
+func TestMoonrise(t *testing.T) {
+	type args struct {
+		observer Observer
+		date     time.Time
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    time.Time
+		wantErr error
+	}{
+		{args: args{observer: london, date: time.Date(2022, 11, 30, 0, 0, 0, 0, time.UTC)}, want: time.Date(2022, 11, 30, 13, 17, 0, 0, time.UTC)},
+		{args: args{observer: london, date: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}, want: time.Date(2022, 1, 1, 6, 55, 0, 0, time.UTC)},
+		{args: args{observer: london, date: time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)}, want: time.Date(2022, 2, 1, 8, 24, 0, 0, time.UTC)},
+		{args: args{observer: london, date: time.Date(2021, 4, 30, 0, 0, 0, 0, time.UTC)}, wantErr: ErrNoMoonrise},
+		{args: args{observer: tromso, date: time.Date(2022, 12, 8, 0, 0, 0, 0, time.UTC)}, wantErr: ErrMoonAlwaysAbove},
+		{args: args{observer: tromso, date: time.Date(2022, 12, 22, 0, 0, 0, 0, time.UTC)}, wantErr: ErrMoonAlwaysBelow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Moonrise(tt.args.observer, tt.args.date)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Moonrise() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			almostEqualTime(t, got, tt.want, 120*time.Second)
+		})
+	}
+}
+
+func TestMoonset(t *testing.T) {
+	type args struct {
+		observer Observer
+		date     time.Time
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    time.Time
+		wantErr error
+	}{
+		{args: args{observer: london, date: time.Date(2021, 10, 28, 0, 0, 0, 0, time.UTC)}, want: time.Date(2021, 10, 28, 14, 11, 0, 0, time.UTC)},
+		{args: args{observer: london, date: time.Date(2021, 11, 6, 0, 0, 0, 0, time.UTC)}, want: time.Date(2021, 11, 6, 17, 21, 0, 0, time.UTC)},
+		{args: args{observer: london, date: time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)}, want: time.Date(2022, 2, 1, 16, 55, 0, 0, time.UTC)},
+		{args: args{observer: london, date: time.Date(2021, 5, 16, 0, 0, 0, 0, time.UTC)}, wantErr: ErrNoMoonset},
+		{args: args{observer: tromso, date: time.Date(2022, 12, 8, 0, 0, 0, 0, time.UTC)}, wantErr: ErrMoonAlwaysAbove},
+		{args: args{observer: tromso, date: time.Date(2022, 12, 22, 0, 0, 0, 0, time.UTC)}, wantErr: ErrMoonAlwaysBelow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Moonset(tt.args.observer, tt.args.date)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Moonset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			almostEqualTime(t, got, tt.want, 120*time.Second)
+		})
+	}
+}
+
+func TestMoonriseLocation(t *testing.T) {
+	// The day starts at midnight in the location of the date.
+	tz := time.FixedZone("UTC+10", 10*60*60)
+	got, err := Moonrise(london, time.Date(2022, 11, 30, 12, 0, 0, 0, tz))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Location() != tz || got.Day() != 30 {
+		t.Fatalf("unexpected moonrise %v", got)
+	}
+}
+
+func TestMoonIllumination(t *testing.T) {
+	// Meeus, Astronomical Algorithms, Example 48.a: 1992 April 12, 0h TD
+	got := moon_illumination(-0.077221081451)
+	almostEqualFloat(t, 0.6786, got.Fraction, 0.0005)
+	almostEqualFloat(t, 69.0756, got.PhaseAngle, 0.05)
+	almostEqualFloat(t, 285.0, got.BrightLimbAngle, 0.1)
+	if !got.Waxing {
+		t.Fatal("expected waxing moon")
+	}
+
+	tests := []struct {
+		name       string
+		date       time.Time
+		want       float64
+		wantWaxing bool
+	}{
+		// new moon 2024-01-11 11:57 UTC
+		{name: "new", date: time.Date(2024, 1, 11, 11, 57, 0, 0, time.UTC), want: 0.0, wantWaxing: false},
+		// first quarter 2024-01-18 03:53 UTC
+		{name: "first quarter", date: time.Date(2024, 1, 18, 3, 53, 0, 0, time.UTC), want: 0.5, wantWaxing: true},
+		// full moon 2024-01-25 17:54 UTC
+		{name: "full", date: time.Date(2024, 1, 25, 17, 54, 0, 0, time.UTC), want: 1.0, wantWaxing: true},
+		// last quarter 2024-02-02 23:18 UTC
+		{name: "last quarter", date: time.Date(2024, 2, 2, 23, 18, 0, 0, time.UTC), want: 0.5, wantWaxing: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MoonIllumination(tt.date)
+			almostEqualFloat(t, tt.want, got.Fraction, 0.01)
+			if tt.name != "new" && tt.name != "full" && got.Waxing != tt.wantWaxing {
+				t.Fatalf("waxing: got %v, want %v", got.Waxing, tt.wantWaxing)
+			}
+		})
+	}
+
+	// The bright limb faces West while waxing and East while waning.
+	waxing := MoonIllumination(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	waning := MoonIllumination(time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC))
+	if !waxing.Waxing || waxing.BrightLimbAngle < 180 {
+		t.Fatalf("unexpected waxing moon %+v", waxing)
+	}
+	if waning.Waxing || waning.BrightLimbAngle > 180 {
+		t.Fatalf("unexpected waning moon %+v", waning)
+	}
+}
+
+func TestNextLunarPhase(t *testing.T) {
+	tests := []struct {
+		after time.Time
+		phase LunarPhase
+		want  time.Time
+	}{
+		{after: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), phase: LunarPhaseNew, want: time.Date(2024, 1, 11, 11, 57, 0, 0, time.UTC)},
+		{after: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), phase: LunarPhaseFirstQuarter, want: time.Date(2024, 1, 18, 3, 53, 0, 0, time.UTC)},
+		{after: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), phase: LunarPhaseFull, want: time.Date(2024, 1, 25, 17, 54, 0, 0, time.UTC)},
+		{after: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), phase: LunarPhaseLastQuarter, want: time.Date(2024, 1, 4, 3, 30, 0, 0, time.UTC)},
+		{after: time.Date(2024, 1, 11, 12, 0, 0, 0, time.UTC), phase: LunarPhaseNew, want: time.Date(2024, 2, 9, 22, 59, 0, 0, time.UTC)},
+		{after: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), phase: LunarPhaseFull, want: time.Date(2024, 3, 25, 7, 0, 0, 0, time.UTC)},
+		{after: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), phase: LunarPhaseNew, want: time.Date(2024, 4, 8, 18, 21, 0, 0, time.UTC)},
+		{after: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), phase: LunarPhaseNew, want: time.Date(2000, 1, 6, 18, 14, 0, 0, time.UTC)},
+		// Meeus, Astronomical Algorithms, Example 49.a: 1977 February 18, 3h37m42s TD
+		{after: time.Date(1977, 2, 1, 0, 0, 0, 0, time.UTC), phase: LunarPhaseNew, want: time.Date(1977, 2, 18, 3, 37, 42, 0, time.UTC).Add(-48 * time.Second)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want.String(), func(t *testing.T) {
+			got := NextLunarPhase(tt.after, tt.phase)
+			almostEqualTime(t, tt.want, got, 3*time.Minute)
+		})
+	}
+}
+
+func TestPreviousLunarPhase(t *testing.T) {
+	tests := []struct {
+		before time.Time
+		phase  LunarPhase
+		want   time.Time
+	}{
+		{before: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), phase: LunarPhaseNew, want: time.Date(2024, 1, 11, 11, 57, 0, 0, time.UTC)},
+		{before: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), phase: LunarPhaseFull, want: time.Date(2024, 1, 25, 17, 54, 0, 0, time.UTC)},
+		{before: time.Date(2024, 1, 11, 11, 0, 0, 0, time.UTC), phase: LunarPhaseNew, want: time.Date(2023, 12, 12, 23, 32, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want.String(), func(t *testing.T) {
+			got := PreviousLunarPhase(tt.before, tt.phase)
+			almostEqualTime(t, tt.want, got, 3*time.Minute)
+		})
+	}
+}
+
+func TestLunarPhaseRoundTrip(t *testing.T) {
+	// the next phase after the previous phase is the previous phase again
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 100; i++ {
+		date := start.Add(time.Duration(i) * 77 * time.Hour)
+		for _, phase := range []LunarPhase{LunarPhaseNew, LunarPhaseFirstQuarter, LunarPhaseFull, LunarPhaseLastQuarter} {
+			prev := PreviousLunarPhase(date, phase)
+			next := NextLunarPhase(date, phase)
+			if !prev.Before(date) || !next.After(date) {
+				t.Fatalf("%v: %v not in (%v, %v)", phase, date, prev, next)
+			}
+			if got := NextLunarPhase(prev, phase); !got.Equal(next) {
+				t.Fatalf("%v: got %v, want %v", phase, got, next)
+			}
+		}
+	}
+}
+
+func TestLunarPhases(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	want := []LunarPhaseEvent{
+		{Phase: LunarPhaseLastQuarter, Time: time.Date(2024, 1, 4, 3, 30, 0, 0, time.UTC)},
+		{Phase: LunarPhaseNew, Time: time.Date(2024, 1, 11, 11, 57, 0, 0, time.UTC)},
+		{Phase: LunarPhaseFirstQuarter, Time: time.Date(2024, 1, 18, 3, 53, 0, 0, time.UTC)},
+		{Phase: LunarPhaseFull, Time: time.Date(2024, 1, 25, 17, 54, 0, 0, time.UTC)},
+	}
+
+	got := LunarPhases(from, to)
+	if len(got) != len(want) {
+		t.Fatalf("got %v phases, want %v: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i].Phase != want[i].Phase {
+			t.Fatalf("got %v, want %v", got[i].Phase, want[i].Phase)
+		}
+		almostEqualTime(t, want[i].Time, got[i].Time, 3*time.Minute)
+	}
+
+	// a full year has 12 or 13 of each phase
+	year := LunarPhases(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if len(year) != 50 {
+		t.Fatalf("got %v phases in 2024, want 50", len(year))
+	}
+	for i := 1; i < len(year); i++ {
+		if year[i].Phase != (year[i-1].Phase+1)%4 {
+			t.Fatalf("unexpected order %v after %v", year[i].Phase, year[i-1].Phase)
+		}
+	}
+}
+
+func TestLunarPhaseIterator(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	want := LunarPhases(from, to)
+	it := NewLunarPhaseIterator(from, to)
+	for i := range want {
+		got, ok := it.Next()
+		if !ok {
+			t.Fatalf("iterator stopped after %v phases, want %v", i, len(want))
+		}
+		if got != want[i] {
+			t.Fatalf("got %v, want %v", got, want[i])
+		}
+	}
+	if got, ok := it.Next(); ok {
+		t.Fatalf("unexpected phase %v after the range", got)
+	}
+	// exhausted iterators stay exhausted
+	if _, ok := it.Next(); ok {
+		t.Fatal("unexpected phase after the end")
+	}
+
+	// the first phase is the earliest one after the start
+	first, ok := NewLunarPhaseIterator(want[0].Time.Add(-time.Minute), to).Next()
+	if !ok || first != want[0] {
+		t.Fatalf("got %v, want %v", first, want[0])
+	}
+}
+
+func TestMoonPhaseNameAt(t *testing.T) {
+	tests := []struct {
+		date time.Time
+		want MoonPhaseName
+	}{
+		{date: time.Date(2024, 1, 11, 12, 0, 0, 0, time.UTC), want: MoonPhaseNew},
+		{date: time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC), want: MoonPhaseWaxingCrescent},
+		{date: time.Date(2024, 1, 18, 4, 0, 0, 0, time.UTC), want: MoonPhaseFirstQuarter},
+		{date: time.Date(2024, 1, 21, 12, 0, 0, 0, time.UTC), want: MoonPhaseWaxingGibbous},
+		{date: time.Date(2024, 1, 25, 18, 0, 0, 0, time.UTC), want: MoonPhaseFull},
+		{date: time.Date(2024, 1, 29, 12, 0, 0, 0, time.UTC), want: MoonPhaseWaningGibbous},
+		{date: time.Date(2024, 2, 2, 23, 0, 0, 0, time.UTC), want: MoonPhaseLastQuarter},
+		{date: time.Date(2024, 2, 6, 12, 0, 0, 0, time.UTC), want: MoonPhaseWaningCrescent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want.String(), func(t *testing.T) {
+			if got := MoonPhaseNameAt(tt.date); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoonPhaseNameLocalized(t *testing.T) {
+	if got := MoonPhaseLastQuarter.String(); got != "Last Quarter" {
+		t.Fatalf("got %q", got)
+	}
+	if got := MoonPhaseWaxingGibbous.Localized(MoonPhaseNamesGerman); got != "Zunehmender Mond" {
+		t.Fatalf("got %q", got)
+	}
+	if got := MoonPhaseFull.Localized(MoonPhaseNamesFrench); got != "Pleine lune" {
+		t.Fatalf("got %q", got)
+	}
+	custom := MoonPhaseNames{"🌑", "🌒", "🌓", "🌔", "🌕", "🌖", "🌗", "🌘"}
+	if got := MoonPhaseWaningCrescent.Localized(custom); got != "🌘" {
+		t.Fatalf("got %q", got)
+	}
+	if got := MoonPhaseName(8).String(); got != "MoonPhaseName(8)" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestMoonPhaseDescription(t *testing.T) {
+	if got, err := MoonPhaseDescription(25); err != nil || got != "Last Quarter" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+}
@@ -0,0 +1,220 @@
+package astral
+
+import (
+	"errors"
+	"time"
+)
+
+// DateRange is a period of whole days, from the midnight of Start until the end of the day of End.
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains reports whether the day of t is part of the range.
+// The day is determined in the location of the range.
+func (r DateRange) Contains(t time.Time) bool {
+	t = t.In(r.Start.Location())
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, r.Start.Location())
+	return !day.Before(r.Start) && !day.After(r.End)
+}
+
+// Days returns the number of days in the range.
+func (r DateRange) Days() int {
+	return int(r.End.Sub(r.Start).Round(24*time.Hour)/(24*time.Hour)) + 1
+}
+
+// Maximum number of days a period is extended into the previous or next year.
+// The periods at the poles last about half a year, the limit only ends the
+// search when the condition is true on every day.
+const maxPeriodDays = 366
+
+// Extend the period backwards while the condition is true on the previous day.
+func extend_period_start(period *DateRange, condition func(date time.Time) bool) {
+	for i := 0; i < maxPeriodDays; i++ {
+		date := period.Start.AddDate(0, 0, -1)
+		if !condition(date) {
+			return
+		}
+		period.Start = date
+	}
+}
+
+// Extend the period forwards while the condition is true on the next day.
+func extend_period_end(period *DateRange, condition func(date time.Time) bool) {
+	for i := 0; i < maxPeriodDays; i++ {
+		date := period.End.AddDate(0, 0, 1)
+		if !condition(date) {
+			return
+		}
+		period.End = date
+	}
+}
+
+// Find the continuous periods of days in the year for which the condition is true.
+// Periods which continue into the previous or next year are extended beyond the boundaries of the year.
+func find_periods(year int, location *time.Location, condition func(date time.Time) bool) []DateRange {
+	var (
+		periods []DateRange
+		current *DateRange
+	)
+	for date := time.Date(year, 1, 1, 0, 0, 0, 0, location); date.Year() == year; date = date.AddDate(0, 0, 1) {
+		if !condition(date) {
+			current = nil
+			continue
+		}
+		if current == nil {
+			periods = append(periods, DateRange{Start: date, End: date})
+			current = &periods[len(periods)-1]
+			continue
+		}
+		current.End = date
+	}
+
+	if len(periods) > 0 {
+		if first := &periods[0]; first.Start.YearDay() == 1 {
+			extend_period_start(first, condition)
+		}
+		if last := &periods[len(periods)-1]; last.End.Year() == year && last.End.AddDate(0, 0, 1).Year() != year {
+			extend_period_end(last, condition)
+		}
+	}
+	return periods
+}
+
+// Find the continuous period of days around the day of date for which the condition is true.
+// The days start in the location of date.
+func find_period_at(date time.Time, condition func(date time.Time) bool) (DateRange, bool) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	if !condition(day) {
+		return DateRange{}, false
+	}
+	period := DateRange{Start: day, End: day}
+	extend_period_start(&period, condition)
+	extend_period_end(&period, condition)
+	return period, true
+}
+
+// Use the location of the observer for the days of the periods, if it has one.
+func period_location(observer Observer, location *time.Location) *time.Location {
+	if observer.Location != nil {
+		return observer.Location
+	}
+	return location
+}
+
+// The conditions of the periods.
+
+func is_polar_day(observer Observer) func(date time.Time) bool {
+	return func(date time.Time) bool {
+		_, err := Sunrise(observer, date)
+		return errors.Is(err, ErrAlwaysAbove)
+	}
+}
+
+func is_polar_night(observer Observer) func(date time.Time) bool {
+	return func(date time.Time) bool {
+		_, err := Sunrise(observer, date)
+		return errors.Is(err, ErrAlwaysBelow)
+	}
+}
+
+func is_no_depression(observer Observer, depression float64) func(date time.Time) bool {
+	return func(date time.Time) bool {
+		_, err := Dusk(observer, date, depression)
+		return errors.Is(err, ErrAlwaysAbove)
+	}
+}
+
+// Calculate the periods of the polar day, when the sun doesn't set.
+// Args:
+//
+//	observer: Observer to calculate the periods for
+//	year:     Year to calculate the periods for
+//	location: Location in which the days start, the location of the observer takes precedence
+//
+// Returns:
+//
+//	The periods overlapping the year ordered by date, periods continuing
+//	into the previous or next year include their days of that year.
+func PolarDayPeriods(observer Observer, year int, location *time.Location) []DateRange {
+	return find_periods(year, period_location(observer, location), is_polar_day(observer))
+}
+
+// Calculate the periods of the polar night, when the sun doesn't rise.
+// Args:
+//
+//	observer: Observer to calculate the periods for
+//	year:     Year to calculate the periods for
+//	location: Location in which the days start, the location of the observer takes precedence
+//
+// Returns:
+//
+//	The periods overlapping the year ordered by date, periods continuing
+//	into the previous or next year include their days of that year.
+func PolarNightPeriods(observer Observer, year int, location *time.Location) []DateRange {
+	return find_periods(year, period_location(observer, location), is_polar_night(observer))
+}
+
+// Calculate the periods when the sun doesn't sink to the given depression,
+// e.g. the periods without astronomical night for DepressionAstronomical.
+// Args:
+//
+//	observer:   Observer to calculate the periods for
+//	year:       Year to calculate the periods for
+//	depression: Number of degrees below the horizon
+//	location:   Location in which the days start, the location of the observer takes precedence
+//
+// Returns:
+//
+//	The periods overlapping the year ordered by date, periods continuing
+//	into the previous or next year include their days of that year.
+func NoDepressionPeriods(observer Observer, year int, depression float64, location *time.Location) []DateRange {
+	return find_periods(year, period_location(observer, location), is_no_depression(observer, depression))
+}
+
+// Calculate the period of the polar day which includes the given date.
+// Only the days around the date are calculated, not the whole year.
+// Args:
+//
+//	observer: Observer to calculate the period for
+//	date:     Date to calculate the period for, the days start in the location
+//	          of the observer, or else in the location of the date
+//
+// Returns:
+//
+//	The period and true, or false if the date isn't part of a polar day.
+func PolarDayAt(observer Observer, date time.Time) (DateRange, bool) {
+	return find_period_at(observer_date(observer, date), is_polar_day(observer))
+}
+
+// Calculate the period of the polar night which includes the given date.
+// Only the days around the date are calculated, not the whole year.
+// Args:
+//
+//	observer: Observer to calculate the period for
+//	date:     Date to calculate the period for, the days start in the location
+//	          of the observer, or else in the location of the date
+//
+// Returns:
+//
+//	The period and true, or false if the date isn't part of a polar night.
+func PolarNightAt(observer Observer, date time.Time) (DateRange, bool) {
+	return find_period_at(observer_date(observer, date), is_polar_night(observer))
+}
+
+// Calculate the period without the given depression which includes the given date.
+// Only the days around the date are calculated, not the whole year.
+// Args:
+//
+//	observer:   Observer to calculate the period for
+//	date:       Date to calculate the period for, the days start in the location
+//	            of the observer, or else in the location of the date
+//	depression: Number of degrees below the horizon
+//
+// Returns:
+//
+//	The period and true, or false if the sun sinks to the depression on the date.
+func NoDepressionAt(observer Observer, date time.Time, depression float64) (DateRange, bool) {
+	return find_period_at(observer_date(observer, date), is_no_depression(observer, depression))
+}
@@ -0,0 +1,187 @@
+package astral
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolarPeriods(t *testing.T) {
+	tromso := Observer{Latitude: 69.6492, Longitude: 18.9553}
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2019, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	// the polar night spans the new year
+	nextYear := func(month time.Month, d int) time.Time {
+		return time.Date(2020, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	previousYear := func(month time.Month, d int) time.Time {
+		return time.Date(2018, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		got  []DateRange
+		want []DateRange
+	}{
+		{
+			name: "polar day",
+			got:  PolarDayPeriods(tromso, 2019, time.UTC),
+			want: []DateRange{{Start: day(time.May, 19), End: day(time.July, 25)}},
+		},
+		{
+			name: "polar night",
+			got:  PolarNightPeriods(tromso, 2019, time.UTC),
+			want: []DateRange{
+				{Start: previousYear(time.November, 23), End: day(time.January, 20)},
+				{Start: day(time.November, 23), End: nextYear(time.January, 20)},
+			},
+		},
+		{
+			name: "no civil night",
+			got:  NoDepressionPeriods(tromso, 2019, DepressionCivil, time.UTC),
+			want: []DateRange{{Start: day(time.April, 29), End: day(time.August, 14)}},
+		},
+		{
+			name: "no astronomical night",
+			got:  NoDepressionPeriods(london, 2019, DepressionAstronomical, time.UTC),
+			want: []DateRange{{Start: day(time.May, 22), End: day(time.July, 21)}},
+		},
+		{
+			name: "no polar day in london",
+			got:  PolarDayPeriods(london, 2019, time.UTC),
+		},
+		{
+			name: "no polar night in london",
+			got:  PolarNightPeriods(london, 2019, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", tt.got, tt.want)
+			}
+			for i := range tt.want {
+				almostEqualTime(t, tt.want[i].Start, tt.got[i].Start, 24*time.Hour)
+				almostEqualTime(t, tt.want[i].End, tt.got[i].End, 24*time.Hour)
+			}
+		})
+	}
+}
+
+func TestPolarPeriodsSouthern(t *testing.T) {
+	// the polar day of the southern hemisphere spans the new year
+	mcmurdo := Observer{Latitude: -77.85, Longitude: 166.67}
+	periods := PolarDayPeriods(mcmurdo, 2019, time.UTC)
+	want := []DateRange{
+		{Start: time.Date(2018, 10, 24, 0, 0, 0, 0, time.UTC), End: time.Date(2019, 2, 19, 0, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2019, 10, 24, 0, 0, 0, 0, time.UTC), End: time.Date(2020, 2, 19, 0, 0, 0, 0, time.UTC)},
+	}
+	if len(periods) != len(want) {
+		t.Fatalf("got %v, want %v", periods, want)
+	}
+	for i := range want {
+		almostEqualTime(t, want[i].Start, periods[i].Start, 24*time.Hour)
+		almostEqualTime(t, want[i].End, periods[i].End, 24*time.Hour)
+	}
+}
+
+func TestPolarPeriodsAt(t *testing.T) {
+	longyearbyen := Observer{Latitude: 78.22, Longitude: 15.65}
+	polarNight := DateRange{
+		Start: time.Date(2019, 10, 24, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2020, 2, 19, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name   string
+		date   time.Time
+		at     func(Observer, time.Time) (DateRange, bool)
+		want   DateRange
+		wantOK bool
+	}{
+		{name: "polar night in december", date: time.Date(2019, 12, 20, 12, 0, 0, 0, time.UTC), at: PolarNightAt, want: polarNight, wantOK: true},
+		{name: "polar night in january", date: time.Date(2020, 1, 5, 12, 0, 0, 0, time.UTC), at: PolarNightAt, want: polarNight, wantOK: true},
+		{name: "no polar day in january", date: time.Date(2020, 1, 5, 12, 0, 0, 0, time.UTC), at: PolarDayAt},
+		{name: "no polar night in june", date: time.Date(2020, 6, 5, 12, 0, 0, 0, time.UTC), at: PolarNightAt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.at(longyearbyen, tt.date)
+			if ok != tt.wantOK {
+				t.Fatalf("got %v, want %v", ok, tt.wantOK)
+			}
+			almostEqualTime(t, tt.want.Start, got.Start, 24*time.Hour)
+			almostEqualTime(t, tt.want.End, got.End, 24*time.Hour)
+		})
+	}
+
+	// the same period as found for the whole year
+	periods := PolarNightPeriods(longyearbyen, 2019, time.UTC)
+	if got := periods[len(periods)-1]; got != polarNight {
+		t.Fatalf("got %v, want %v", got, polarNight)
+	}
+
+	got, ok := NoDepressionAt(longyearbyen, time.Date(2020, 6, 5, 12, 0, 0, 0, time.UTC), DepressionAstronomical)
+	if !ok || !got.Contains(time.Date(2020, 3, 21, 0, 0, 0, 0, time.UTC)) || !got.Contains(time.Date(2020, 9, 23, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("got %v, %v", got, ok)
+	}
+}
+
+func TestDateRange(t *testing.T) {
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	r := DateRange{
+		Start: time.Date(2019, 5, 19, 0, 0, 0, 0, zone),
+		End:   time.Date(2019, 7, 25, 0, 0, 0, 0, zone),
+	}
+
+	if got := r.Days(); got != 68 {
+		t.Fatalf("got %v days, want 68", got)
+	}
+
+	tests := []struct {
+		t    time.Time
+		want bool
+	}{
+		{t: time.Date(2019, 5, 18, 23, 59, 0, 0, zone), want: false},
+		{t: time.Date(2019, 5, 19, 0, 0, 0, 0, zone), want: true},
+		{t: time.Date(2019, 7, 25, 23, 59, 0, 0, zone), want: true},
+		{t: time.Date(2019, 7, 26, 0, 0, 0, 0, zone), want: false},
+		// already the next day in the location of the range
+		{t: time.Date(2019, 5, 18, 22, 30, 0, 0, time.UTC), want: true},
+		{t: time.Date(2019, 7, 25, 22, 30, 0, 0, time.UTC), want: false},
+	}
+	for _, tt := range tests {
+		if got := r.Contains(tt.t); got != tt.want {
+			t.Errorf("Contains(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestPolarPeriodsObserverLocation(t *testing.T) {
+	oslo, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	longyearbyen := Observer{Latitude: 78.22, Longitude: 15.65, Location: oslo}
+
+	// the location of the observer takes precedence
+	periods := PolarNightPeriods(longyearbyen, 2019, time.UTC)
+	if len(periods) == 0 {
+		t.Fatal("no polar night")
+	}
+	for _, period := range periods {
+		if period.Start.Location() != oslo || period.End.Location() != oslo {
+			t.Fatalf("got locations %v and %v, want %v", period.Start.Location(), period.End.Location(), oslo)
+		}
+	}
+
+	// already the next day in the location of the observer
+	date := time.Date(2019, 10, 23, 23, 30, 0, 0, time.UTC)
+	got, ok := PolarNightAt(longyearbyen, date)
+	if !ok {
+		t.Fatalf("%v isn't part of the polar night", date)
+	}
+	if got != periods[len(periods)-1] {
+		t.Fatalf("got %v, want %v", got, periods[len(periods)-1])
+	}
+}
@@ -0,0 +1,59 @@
+package astral
+
+import "time"
+
+// A crossing is the time when a function changes its sign.
+type crossing struct {
+	time time.Time
+	// rising is true when the function changes from negative to positive.
+	rising bool
+}
+
+// Find the times between start and end when f changes its sign.
+// The range is sampled with the given step and each crossing is
+// refined by bisection to half a second.
+// Args:
+//
+//	start: Start of the range to search
+//	end:   End of the range to search
+//	step:  Width of the steps used for sampling the range
+//	f:     Function to search the crossings for, e.g. the elevation above the horizon
+//
+// Returns:
+//
+//	The crossings ordered by time and whether f was ever positive and ever negative (or zero).
+func find_crossings(start, end time.Time, step time.Duration, f func(time.Time) float64) ([]crossing, bool, bool) {
+	var (
+		crossings []crossing
+		prevTime  = start
+		prev      = f(start)
+		above     = prev > 0
+		below     = prev <= 0
+	)
+	for t := start.Add(step); !t.After(end); t = t.Add(step) {
+		cur := f(t)
+		above = above || cur > 0
+		below = below || cur <= 0
+
+		if (prev > 0) != (cur > 0) {
+			crossings = append(crossings, crossing{time: bisect(prevTime, t, f), rising: cur > 0})
+		}
+
+		prevTime, prev = t, cur
+	}
+	return crossings, above, below
+}
+
+// Find the time between start and end when f changes its sign.
+func bisect(start, end time.Time, f func(time.Time) float64) time.Time {
+	startAbove := f(start) > 0
+	for end.Sub(start) > time.Second/2 {
+		mid := start.Add(end.Sub(start) / 2)
+		if (f(mid) > 0) == startAbove {
+			start = mid
+		} else {
+			end = mid
+		}
+	}
+	return start.Add(end.Sub(start) / 2)
+}
@@ -0,0 +1,198 @@
+package astral
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Season is one of the four astronomical seasons.
+type Season int
+
+const (
+	SeasonSpring Season = iota
+	SeasonSummer
+	SeasonAutumn
+	SeasonWinter
+)
+
+func (s Season) String() string {
+	switch s {
+	case SeasonSpring:
+		return "Spring"
+	case SeasonSummer:
+		return "Summer"
+	case SeasonAutumn:
+		return "Autumn"
+	case SeasonWinter:
+		return "Winter"
+	}
+	return fmt.Sprintf("Season(%d)", int(s))
+}
+
+// Hemisphere of the earth, the seasons of the southern hemisphere are opposite to the northern ones.
+type Hemisphere int
+
+const (
+	HemisphereNorthern Hemisphere = iota
+	HemisphereSouthern
+)
+
+// Mean instants of the equinoxes and solstices as polynomials in the year,
+// in the order March, June, September and December.
+// See Meeus, Astronomical Algorithms, Tables 27.A and 27.C.
+var (
+	// for the years -1000 to +1000, with Y = year/1000
+	meanSeasonTermsBefore1000 = [4][5]float64{
+		{1721139.29189, 365242.13740, 0.06134, 0.00111, -0.00071},
+		{1721233.25401, 365241.72562, -0.05323, 0.00907, 0.00025},
+		{1721325.70455, 365242.49558, -0.11677, -0.00297, 0.00074},
+		{1721414.39987, 365242.88257, -0.00769, -0.00933, -0.00006},
+	}
+	// for the years +1000 to +3000, with Y = (year-2000)/1000
+	meanSeasonTermsAfter1000 = [4][5]float64{
+		{2451623.80984, 365242.37404, 0.05169, -0.00411, -0.00057},
+		{2451716.56767, 365241.62603, 0.00325, 0.00888, -0.00030},
+		{2451810.21715, 365242.01767, -0.11575, 0.00337, 0.00078},
+		{2451900.05952, 365242.74049, -0.06223, -0.00823, 0.00032},
+	}
+)
+
+// Periodic terms (a, b in degrees, c in degrees per Julian century) for the equinoxes and solstices.
+// See Meeus, Astronomical Algorithms, Table 27.B.
+var seasonTerms = []struct{ a, b, c float64 }{
+	{485, 324.96, 1934.136},
+	{203, 337.23, 32964.467},
+	{199, 342.08, 20.186},
+	{182, 27.85, 445267.112},
+	{156, 73.14, 45036.886},
+	{136, 171.52, 22518.443},
+	{77, 222.54, 65928.934},
+	{74, 296.72, 3034.906},
+	{70, 243.58, 9037.513},
+	{58, 119.81, 33718.147},
+	{52, 297.17, 150.678},
+	{50, 21.02, 2281.226},
+	{45, 247.54, 29929.562},
+	{44, 325.15, 31555.956},
+	{29, 60.93, 4443.417},
+	{18, 155.12, 67555.328},
+	{17, 288.79, 4562.452},
+	{16, 198.04, 62894.029},
+	{14, 199.76, 31436.921},
+	{12, 95.39, 14577.848},
+	{12, 287.11, 31931.756},
+	{12, 320.81, 34777.259},
+	{9, 227.73, 1222.114},
+	{8, 15.45, 16859.074},
+}
+
+// Calculate the Julian Day in Terrestrial Time of an equinox or solstice.
+// See Meeus, Astronomical Algorithms, Chapter 27.
+// The tables are valid for the years -1000 to 3000, outside of them the result
+// drifts away from the real instant and is only usable as a starting guess.
+// Args:
+//
+//	year:    Year to calculate the instant for
+//	quarter: 0 for the March equinox, 1 for the June solstice,
+//	         2 for the September equinox and 3 for the December solstice
+func season_start_jde(year int, quarter int) float64 {
+	terms := meanSeasonTermsAfter1000[quarter]
+	y := (float64(year) - 2000.0) / 1000.0
+	if year < 1000 {
+		terms = meanSeasonTermsBefore1000[quarter]
+		y = float64(year) / 1000.0
+	}
+
+	jde0 := terms[0] + y*(terms[1]+y*(terms[2]+y*(terms[3]+y*terms[4])))
+
+	t := jday_to_jcentury(jde0)
+	w := radians(35999.373*t - 2.47)
+	dl := 1 + 0.0334*math.Cos(w) + 0.0007*math.Cos(2*w)
+
+	s := 0.0
+	for _, term := range seasonTerms {
+		s += term.a * math.Cos(radians(term.b+term.c*t))
+	}
+
+	return jde0 + 0.00001*s/dl
+}
+
+// Width of the range around the estimate of season_start_jde which is searched
+// for the instant of an equinox or solstice. The sun moves about one degree per day,
+// so the range also covers the drift of the estimate outside of the years -1000 to 3000.
+const seasonSearchRange = 10 * 24 * time.Hour
+
+// Calculate the instant of an equinox or solstice in UTC, i.e. when the
+// apparent longitude of the sun reaches a multiple of 90 degrees.
+// The instant is searched around the estimate of season_start_jde.
+func season_start(year int, quarter int) time.Time {
+	jde := season_start_jde(year, quarter)
+	// convert from Terrestrial Time to Universal Time
+	estimate := TimeFromJulianDay(jde - delta_t(jde)/86400.0)
+
+	longitude := float64(quarter) * 90.0
+	// difference to the longitude in the range -180 to 180 degrees,
+	// increasing through zero at the instant
+	f := func(t time.Time) float64 {
+		return properAngle(sun_apparent_long(jday_to_jcentury_tt(JulianDay(t)))-longitude+180.0) - 180.0
+	}
+
+	return bisect(estimate.Add(-seasonSearchRange), estimate.Add(seasonSearchRange), f).Round(time.Second)
+}
+
+// MarchEquinox returns the instant in UTC when the sun crosses the celestial
+// equator northwards in the given year, i.e. its apparent longitude is 0 degrees.
+func MarchEquinox(year int) time.Time {
+	return season_start(year, 0)
+}
+
+// JuneSolstice returns the instant in UTC when the sun reaches its northernmost
+// declination in the given year, i.e. its apparent longitude is 90 degrees.
+func JuneSolstice(year int) time.Time {
+	return season_start(year, 1)
+}
+
+// SeptemberEquinox returns the instant in UTC when the sun crosses the celestial
+// equator southwards in the given year, i.e. its apparent longitude is 180 degrees.
+func SeptemberEquinox(year int) time.Time {
+	return season_start(year, 2)
+}
+
+// DecemberSolstice returns the instant in UTC when the sun reaches its southernmost
+// declination in the given year, i.e. its apparent longitude is 270 degrees.
+func DecemberSolstice(year int) time.Time {
+	return season_start(year, 3)
+}
+
+// Calculate the astronomical season at the given instant.
+// The seasons start at the equinoxes and solstices, e.g. spring in the northern
+// hemisphere lasts from the March equinox until the June solstice.
+// The season only depends on the instant, not on its location or the one of an observer.
+// Args:
+//
+//	dateandtime: The date and time to calculate the season for
+//	hemisphere:  Hemisphere of the earth to calculate the season for
+//
+// Returns:
+//
+//	The season.
+func SeasonAt(dateandtime time.Time, hemisphere Hemisphere) Season {
+	year := dateandtime.UTC().Year()
+
+	// before the March equinox it's still the season which started
+	// with the December solstice of the previous year
+	quarter := 3
+	for q := 3; q >= 0; q-- {
+		if !dateandtime.Before(season_start(year, q)) {
+			quarter = q
+			break
+		}
+	}
+
+	season := Season(quarter)
+	if hemisphere == HemisphereSouthern {
+		season = (season + 2) % 4
+	}
+	return season
+}
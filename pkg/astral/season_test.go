@@ -0,0 +1,93 @@
+package astral
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSeasonStartJDE(t *testing.T) {
+	// Meeus, Astronomical Algorithms, Example 27.a
+	almostEqualFloat(t, 2437837.39245, season_start_jde(1962, 1), 0.00001)
+}
+
+func TestEquinoxesAndSolstices(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(int) time.Time
+		year int
+		want time.Time
+	}{
+		{name: "march 2000", fn: MarchEquinox, year: 2000, want: time.Date(2000, 3, 20, 7, 35, 0, 0, time.UTC)},
+		{name: "june 2000", fn: JuneSolstice, year: 2000, want: time.Date(2000, 6, 21, 1, 48, 0, 0, time.UTC)},
+		{name: "september 2000", fn: SeptemberEquinox, year: 2000, want: time.Date(2000, 9, 22, 17, 27, 0, 0, time.UTC)},
+		{name: "december 2000", fn: DecemberSolstice, year: 2000, want: time.Date(2000, 12, 21, 13, 37, 0, 0, time.UTC)},
+		{name: "march 2024", fn: MarchEquinox, year: 2024, want: time.Date(2024, 3, 20, 3, 6, 0, 0, time.UTC)},
+		{name: "june 2024", fn: JuneSolstice, year: 2024, want: time.Date(2024, 6, 20, 20, 51, 0, 0, time.UTC)},
+		{name: "september 2024", fn: SeptemberEquinox, year: 2024, want: time.Date(2024, 9, 22, 12, 44, 0, 0, time.UTC)},
+		{name: "december 2024", fn: DecemberSolstice, year: 2024, want: time.Date(2024, 12, 21, 9, 20, 0, 0, time.UTC)},
+		{name: "march 2030", fn: MarchEquinox, year: 2030, want: time.Date(2030, 3, 20, 13, 52, 0, 0, time.UTC)},
+		{name: "december 2030", fn: DecemberSolstice, year: 2030, want: time.Date(2030, 12, 21, 20, 9, 0, 0, time.UTC)},
+		// Meeus, Astronomical Algorithms, Example 27.a: 1962 June 21, 21h25m TD
+		{name: "june 1962", fn: JuneSolstice, year: 1962, want: time.Date(1962, 6, 21, 21, 25, 8, 0, time.UTC).Add(-34 * time.Second)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.fn(tt.year)
+			// the apparent longitude of the sun is accurate to about 0.01 degrees,
+			// which the sun passes in about 15 minutes
+			almostEqualTime(t, tt.want, got, 10*time.Minute)
+		})
+	}
+}
+
+func TestEquinoxesAndSolsticesLongitude(t *testing.T) {
+	fns := []func(int) time.Time{MarchEquinox, JuneSolstice, SeptemberEquinox, DecemberSolstice}
+	for _, year := range []int{-2000, -1000, 0, 1000, 1962, 2024, 3000, 4000} {
+		for quarter, fn := range fns {
+			got := fn(year)
+			longitude := SunEphemeris(got).ApparentLongitude
+			// 360 degrees for the March equinox
+			diff := math.Remainder(longitude-float64(quarter)*90, 360)
+			almostEqualFloat(t, 0, diff, 0.0001)
+		}
+	}
+}
+
+func TestSeasonAt(t *testing.T) {
+	tests := []struct {
+		date     time.Time
+		northern Season
+		southern Season
+	}{
+		{date: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), northern: SeasonWinter, southern: SeasonSummer},
+		{date: time.Date(2024, 3, 20, 3, 0, 0, 0, time.UTC), northern: SeasonWinter, southern: SeasonSummer},
+		{date: time.Date(2024, 3, 20, 3, 10, 0, 0, time.UTC), northern: SeasonSpring, southern: SeasonAutumn},
+		{date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), northern: SeasonSpring, southern: SeasonAutumn},
+		{date: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), northern: SeasonSummer, southern: SeasonWinter},
+		{date: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), northern: SeasonAutumn, southern: SeasonSpring},
+		{date: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), northern: SeasonWinter, southern: SeasonSummer},
+		// local time before the equinox, but already after it in UTC
+		{date: time.Date(2024, 3, 19, 23, 30, 0, 0, time.FixedZone("EDT", -4*60*60)), northern: SeasonSpring, southern: SeasonAutumn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.date.String(), func(t *testing.T) {
+			if got := SeasonAt(tt.date, HemisphereNorthern); got != tt.northern {
+				t.Fatalf("northern: got %v, want %v", got, tt.northern)
+			}
+			if got := SeasonAt(tt.date, HemisphereSouthern); got != tt.southern {
+				t.Fatalf("southern: got %v, want %v", got, tt.southern)
+			}
+		})
+	}
+
+	// the instants of the equinoxes and solstices start the seasons
+	for year := 1990; year < 2030; year++ {
+		starts := []time.Time{MarchEquinox(year), JuneSolstice(year), SeptemberEquinox(year), DecemberSolstice(year)}
+		for i, start := range starts {
+			if got := SeasonAt(start, HemisphereNorthern); got != Season(i) {
+				t.Fatalf("%v: got %v, want %v", start, got, Season(i))
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package astral
+
+import (
+	"math"
+	"time"
+)
+
+// Calculate the difference between local apparent solar time and UTC in minutes.
+func apparent_solar_time_offset(longitude float64, juliancentury float64) float64 {
+	return eq_of_time(juliancentury) + 4.0*longitude
+}
+
+// Create the zone of a solar time with the given offset to UTC in minutes.
+// Zones can only be offset by full seconds, so the offset is rounded.
+func solar_time_zone(name string, offset float64) *time.Location {
+	return time.FixedZone(name, int(math.Round(offset*60)))
+}
+
+// Interpret the wall clock of t as UTC.
+func wall_clock_as_utc(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// Convert a civil time to the local mean solar time of the observer.
+// The mean solar time differs from UTC by 4 minutes per degree of longitude.
+// Args:
+//
+//	observer:    Observer to calculate the solar time for
+//	dateandtime: The instant to convert
+//
+// Returns:
+//
+//	The same instant in the zone "LMT", whose wall clock shows the local mean solar time.
+func MeanSolarTime(observer Observer, dateandtime time.Time) time.Time {
+	return dateandtime.In(solar_time_zone("LMT", 4.0*observer.Longitude))
+}
+
+// Convert a civil time to the local apparent (true) solar time of the observer,
+// as shown by a sundial. It differs from the mean solar time by the equation of time.
+// Args:
+//
+//	observer:    Observer to calculate the solar time for
+//	dateandtime: The instant to convert
+//
+// Returns:
+//
+//	The same instant in the zone "LAT", whose wall clock shows the local apparent solar time.
+func ApparentSolarTime(observer Observer, dateandtime time.Time) time.Time {
+	offset := apparent_solar_time_offset(observer.Longitude, jday_to_jcentury_tt(JulianDay(dateandtime)))
+	return dateandtime.In(solar_time_zone("LAT", offset))
+}
+
+// Convert a local mean solar time of the observer to a civil time.
+// Args:
+//
+//	observer:  Observer the solar time belongs to
+//	solartime: The wall clock of the time is used as the local mean solar time, its location is ignored.
+//
+// Returns:
+//
+//	The instant in UTC.
+func TimeFromMeanSolarTime(observer Observer, solartime time.Time) time.Time {
+	offset := time.Duration(4.0 * observer.Longitude * float64(time.Minute))
+	return wall_clock_as_utc(solartime).Add(-offset)
+}
+
+// Convert a local apparent (true) solar time of the observer to a civil time.
+// Args:
+//
+//	observer:  Observer the solar time belongs to
+//	solartime: The wall clock of the time is used as the local apparent solar time, its location is ignored.
+//
+// Returns:
+//
+//	The instant in UTC.
+func TimeFromApparentSolarTime(observer Observer, solartime time.Time) time.Time {
+	wall := wall_clock_as_utc(solartime)
+
+	// the equation of time changes by less than 30 seconds per day,
+	// so a few iterations are enough to find the instant
+	utc := TimeFromMeanSolarTime(observer, solartime)
+	for i := 0; i < 3; i++ {
+		offset := apparent_solar_time_offset(observer.Longitude, jday_to_jcentury_tt(JulianDay(utc)))
+		utc = wall.Add(-time.Duration(offset * float64(time.Minute)))
+	}
+	return utc
+}
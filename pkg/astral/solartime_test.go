@@ -0,0 +1,81 @@
+package astral
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMeanSolarTime(t *testing.T) {
+	tests := []struct {
+		observer Observer
+		date     time.Time
+		want     time.Time
+	}{
+		{observer: Observer{Longitude: 0}, date: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC), want: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)},
+		{observer: Observer{Longitude: 15}, date: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC), want: time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC)},
+		{observer: Observer{Longitude: -90}, date: time.Date(2024, 6, 1, 3, 0, 0, 0, time.UTC), want: time.Date(2024, 5, 31, 21, 0, 0, 0, time.UTC)},
+		{observer: london, date: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC), want: time.Date(2024, 6, 1, 11, 59, 32, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.date.String(), func(t *testing.T) {
+			got := MeanSolarTime(tt.observer, tt.date)
+			if !got.Equal(tt.date) {
+				t.Fatalf("instant changed: %v != %v", got, tt.date)
+			}
+			if got.Location().String() != "LMT" {
+				t.Fatalf("unexpected zone %v", got.Location())
+			}
+			almostEqualTime(t, tt.want, wall_clock_as_utc(got), time.Second)
+		})
+	}
+}
+
+func TestApparentSolarTime(t *testing.T) {
+	// at Greenwich the apparent solar time differs from UTC by the equation of time
+	greenwich := Observer{Latitude: 51.4769, Longitude: 0}
+	got := ApparentSolarTime(greenwich, time.Date(2024, 11, 3, 12, 0, 0, 0, time.UTC))
+	if got.Location().String() != "LAT" {
+		t.Fatalf("unexpected zone %v", got.Location())
+	}
+	almostEqualTime(t, time.Date(2024, 11, 3, 12, 16, 25, 0, time.UTC), wall_clock_as_utc(got), 5*time.Second)
+
+	got = ApparentSolarTime(greenwich, time.Date(2024, 2, 11, 12, 0, 0, 0, time.UTC))
+	almostEqualTime(t, time.Date(2024, 2, 11, 11, 45, 46, 0, time.UTC), wall_clock_as_utc(got), 5*time.Second)
+
+	// the sun transits the meridian at 12:00 apparent solar time
+	for _, observer := range []Observer{london, newDelhi, sydney, reykjavik} {
+		for _, date := range []time.Time{
+			time.Date(2024, 2, 11, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 14, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 7, 26, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 11, 3, 0, 0, 0, 0, time.UTC),
+		} {
+			got := wall_clock_as_utc(ApparentSolarTime(observer, Noon(observer, date)))
+			almostEqualTime(t, time.Date(got.Year(), got.Month(), got.Day(), 12, 0, 0, 0, time.UTC), got, 2*time.Second)
+		}
+	}
+}
+
+func TestSolarTimeRoundTrip(t *testing.T) {
+	zone := time.FixedZone("UTC+10", 10*60*60)
+	for _, observer := range []Observer{london, newDelhi, sydney, {Longitude: 179.9}, {Longitude: -179.9}} {
+		for _, date := range []time.Time{
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 2, 11, 23, 59, 59, 0, time.UTC),
+			time.Date(2024, 11, 3, 6, 30, 0, 0, zone),
+		} {
+			mean := MeanSolarTime(observer, date)
+			almostEqualTime(t, date, TimeFromMeanSolarTime(observer, mean), time.Second)
+
+			apparent := ApparentSolarTime(observer, date)
+			almostEqualTime(t, date, TimeFromApparentSolarTime(observer, apparent), time.Second)
+		}
+	}
+
+	// the location of the solar time is ignored
+	solar := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	inZone := time.Date(2024, 6, 1, 12, 0, 0, 0, zone)
+	if got, want := TimeFromMeanSolarTime(sydney, inZone), TimeFromMeanSolarTime(sydney, solar); !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
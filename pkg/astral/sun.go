@@ -35,6 +35,92 @@ type Observer struct {
 	Latitude  float64
 	Longitude float64
 	Elevation float64
+	// Obstruction is an optional feature which hides the horizon from the
+	// observer. When set, it replaces the adjustment for the elevation.
+	Obstruction *Obstruction
+	// Atmosphere is the optional state of the air at the observer, which scales the
+	// refraction. When nil or invalid (see Atmosphere.Validate), a standard atmosphere
+	// of 1010 hPa and 10 °C is used.
+	Atmosphere *Atmosphere
+	// NoRefraction disables the refraction, the sun and moon are at their
+	// geometric positions.
+	NoRefraction bool
+	// Location is the optional time zone of the observer. When set, the day of a
+	// date is taken in this location and the times are returned in it.
+	// When nil, the location of the date is used.
+	Location *time.Location
+}
+
+// Convert the date to the location of the observer, if it has one.
+func observer_date(observer Observer, date time.Time) time.Time {
+	if observer.Location == nil {
+		return date
+	}
+	return date.In(observer.Location)
+}
+
+// Calculate an event on the day of the date in the location of the observer.
+// The calculation gets the day as UTC midnight. Like astral 3, the neighbouring
+// day is calculated when the event falls on another day in the location.
+func on_observer_day(observer Observer, date time.Time, calc func(day time.Time) (time.Time, error)) (time.Time, error) {
+	local := date.In(observer.Location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	t, err := calc(day)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	got := t.In(observer.Location)
+	gotDay := time.Date(got.Year(), got.Month(), got.Day(), 0, 0, 0, 0, time.UTC)
+	if !gotDay.Equal(day) {
+		offset := 1
+		if gotDay.After(day) {
+			offset = -1
+		}
+		if t, err = calc(day.AddDate(0, 0, offset)); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return t.In(observer.Location), nil
+}
+
+// Atmosphere describes the air at the observer for calculating the refraction.
+type Atmosphere struct {
+	// Pressure of the air in hPa (millibar), must be positive.
+	Pressure float64
+	// Temperature of the air in degrees Celsius, must be above -273 °C.
+	Temperature float64
+}
+
+var ErrInvalidAtmosphere = errors.New("invalid atmosphere")
+
+// Validate checks that the pressure is positive and the temperature is above
+// absolute zero, otherwise the refraction would be negative or infinite.
+func (a Atmosphere) Validate() error {
+	if !(a.Pressure > 0) {
+		return fmt.Errorf("%w: pressure of %v hPa isn't positive", ErrInvalidAtmosphere, a.Pressure)
+	}
+	if !(a.Temperature > -273.0) {
+		return fmt.Errorf("%w: temperature of %v °C isn't above absolute zero", ErrInvalidAtmosphere, a.Temperature)
+	}
+	return nil
+}
+
+// The standard atmosphere used by the refraction formulas.
+const (
+	standardPressure    = 1010.0
+	standardTemperature = 10.0
+)
+
+// Obstruction describes a feature, e.g. a ridge or a building, between the
+// observer and the horizon.
+type Obstruction struct {
+	// Height of the feature relative to the observer in metres.
+	// Negative values describe a feature below the observer.
+	Height float64
+	// Distance from the observer to the feature in metres.
+	Distance float64
 }
 
 // Convert a floating point number of minutes to a time.Duration
@@ -81,17 +167,18 @@ func sun_true_long(juliancentury float64) float64 {
 }
 
 // Calculate the sun's true anomaly//
-// func sun_true_anomoly(juliancentury float64) float64 {
-// 	m := geom_mean_anomaly_sun(juliancentury)
-// 	c := sun_eq_of_center(juliancentury)
-// 	return m + c
-// }
-
-// func sun_rad_vector(juliancentury float64) float64 {
-// 	v := sun_true_anomoly(juliancentury)
-// 	e := eccentric_location_earth_orbit(juliancentury)
-// 	return (1.000001018 * (1 - e*e)) / (1 + e*math.Cos(radians(v)))
-// }
+func sun_true_anomoly(juliancentury float64) float64 {
+	m := geom_mean_anomaly_sun(juliancentury)
+	c := sun_eq_of_center(juliancentury)
+	return m + c
+}
+
+// Calculate the distance between the earth and the sun in AU
+func sun_rad_vector(juliancentury float64) float64 {
+	v := sun_true_anomoly(juliancentury)
+	e := eccentric_location_earth_orbit(juliancentury)
+	return (1.000001018 * (1 - e*e)) / (1 + e*math.Cos(radians(v)))
+}
 
 func sun_apparent_long(juliancentury float64) float64 {
 	true_long := sun_true_long(juliancentury)
@@ -113,14 +200,14 @@ func obliquity_correction(juliancentury float64) float64 {
 }
 
 // Calculate the sun's right ascension
-// func sun_rt_ascension(juliancentury float64) float64 {
-// 	oc := obliquity_correction(juliancentury)
-// 	al := sun_apparent_long(juliancentury)
+func sun_rt_ascension(juliancentury float64) float64 {
+	oc := obliquity_correction(juliancentury)
+	al := sun_apparent_long(juliancentury)
 
-// 	tananum := math.Cos(radians(oc)) * math.Sin(radians(al))
-// 	tanadenom := math.Cos(radians(al))
-// 	return degrees(math.Atan2(tananum, tanadenom))
-// }
+	tananum := math.Cos(radians(oc)) * math.Sin(radians(al))
+	tanadenom := math.Cos(radians(al))
+	return degrees(math.Atan2(tananum, tanadenom))
+}
 
 // Calculate the sun's declination
 func sun_declination(juliancentury float64) float64 {
@@ -167,7 +254,7 @@ func eq_of_time(juliancentury float64) float64 {
 //
 // Raises:
 //
-//	ValueError
+//	EventError: if the sun doesn't reach the zenith angle
 func hour_angle(latitude float64, declination float64, zenith float64, direction SunDirection) (float64, error) {
 	latitude_rad := radians(latitude)
 	declination_rad := radians(declination)
@@ -177,7 +264,8 @@ func hour_angle(latitude float64, declination float64, zenith float64, direction
 
 	hourAngle := math.Acos(h)
 	if math.IsNaN(hourAngle) {
-		return 0, errors.New("not able to determine hour angle")
+		// the sun never gets closer to the zenith (h > 1) or never gets further away from it (h < -1)
+		return 0, &EventError{Elevation: 90 - zenith, AlwaysBelow: h > 1, AlwaysAbove: h < -1}
 	}
 	if direction == SunDirectionSetting {
 		hourAngle = -hourAngle
@@ -221,6 +309,40 @@ func adjust_to_obscuring_feature(elevation0, elevation1 float64) float64 {
 	return sign * degrees(math.Acos(math.Abs(elevation0)/math.Sqrt(math.Pow(elevation0, 2)+math.Pow(elevation1, 2))))
 }
 
+// Calculate the number of degrees to adjust the zenith for the observer,
+// either due to the elevation or due to an obscuring feature.
+func adjust_for_observer(observer Observer) float64 {
+	if observer.Obstruction != nil {
+		if observer.Obstruction.Height == 0.0 {
+			return 0.0
+		}
+		// The angle is measured between the vertical and the top of the
+		// feature, the horizon is moved by its complement.
+		angle := adjust_to_obscuring_feature(observer.Obstruction.Height, observer.Obstruction.Distance)
+		if angle > 0.0 {
+			return angle - 90.0
+		}
+		return angle + 90.0
+	}
+	return adjust_to_horizon(observer.Elevation)
+}
+
+// Calculate the degrees of refraction for the atmosphere of the observer.
+// The refraction of the standard atmosphere is scaled by the pressure and the temperature.
+// See Meeus, Astronomical Algorithms, Chapter 16.
+func refraction_for_observer(observer Observer, zenith float64) float64 {
+	if observer.NoRefraction {
+		return 0
+	}
+	refraction := refraction_at_zenith(zenith)
+	if observer.Atmosphere == nil || observer.Atmosphere.Validate() != nil {
+		return refraction
+	}
+	pressure := observer.Atmosphere.Pressure / standardPressure
+	temperature := (273.0 + standardTemperature) / (273.0 + observer.Atmosphere.Temperature)
+	return refraction * pressure * temperature
+}
+
 // Calculate the degrees of refraction of the sun due to the sun's elevation.
 func refraction_at_zenith(zenith float64) float64 {
 
@@ -256,12 +378,22 @@ func refraction_at_zenith(zenith float64) float64 {
 //
 // Raises:
 //
-//	ValueError if the zenith is not transitted by the sun
+//	EventError if the zenith is not transitted by the sun
 //
 // Returns:
 //
 //	the time when the sun transits the specificed zenith
 func time_of_transit(observer Observer, date time.Time, zenith float64, direction SunDirection) (time.Time, error) {
+	if observer.Location == nil {
+		return transit_on_day(observer, date, zenith, direction)
+	}
+	return on_observer_day(observer, date, func(day time.Time) (time.Time, error) {
+		return transit_on_day(observer, day, zenith, direction)
+	})
+}
+
+// Calculate the time in the UTC day of the date when the sun transits the specified zenith.
+func transit_on_day(observer Observer, date time.Time, zenith float64, direction SunDirection) (time.Time, error) {
 	latitude := observer.Latitude
 	if observer.Latitude > 89.8 {
 		latitude = 89.8
@@ -269,21 +401,11 @@ func time_of_transit(observer Observer, date time.Time, zenith float64, directio
 		latitude = -89.8
 	}
 
-	// TODO:
-	// if isinstance(observer.elevation, float) && observer.elevation > 0.0 {
-	// 	adjustment_for_elevation = adjust_to_horizon(observer.elevation)
-	adjustment_for_elevation := 0.0
-	if observer.Elevation > 0.0 {
-		adjustment_for_elevation = adjust_to_horizon(observer.Elevation)
-	}
-	// } else if isinstance(observer.elevation, tuple) {
-	// 	adjustment_for_elevation = adjust_to_obscuring_feature(observer.elevation)
-	// }
-
-	adjustment_for_refraction := refraction_at_zenith(zenith + adjustment_for_elevation)
+	adjustment_for_elevation := adjust_for_observer(observer)
+	adjustment_for_refraction := refraction_for_observer(observer, zenith+adjustment_for_elevation)
 
 	jd := julianday(date)
-	jc := jday_to_jcentury(jd)
+	jc := jday_to_jcentury_tt(jd)
 	solarDec := sun_declination(jc)
 
 	hourangle, err := hour_angle(latitude, solarDec, zenith+adjustment_for_elevation-adjustment_for_refraction, direction)
@@ -338,7 +460,7 @@ func TimeAtElevation(observer Observer, elevation float64, date time.Time, direc
 	zenith := 90 - elevation
 	t, err := time_of_transit(observer, date, zenith, direction)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("sun never reaches an elevation of %v degrees at this location", elevation)
+		return time.Time{}, event_error(err, 0, elevation)
 	}
 	return t, nil
 }
@@ -353,7 +475,18 @@ func TimeAtElevation(observer Observer, elevation float64, date time.Time, direc
 //
 //	Date and time at which noon occurs.
 func Noon(observer Observer, date time.Time) time.Time {
-	jc := jday_to_jcentury(julianday(date))
+	if observer.Location == nil {
+		return noon_on_day(observer, date)
+	}
+	noon, _ := on_observer_day(observer, date, func(day time.Time) (time.Time, error) {
+		return noon_on_day(observer, day), nil
+	})
+	return noon
+}
+
+// Calculate the solar noon in the UTC day of the date.
+func noon_on_day(observer Observer, date time.Time) time.Time {
+	jc := jday_to_jcentury_tt(julianday(date))
 	eqtime := eq_of_time(jc)
 	timeUTC := (720.0 - (4 * observer.Longitude) - eqtime) / 60.0
 
@@ -402,9 +535,20 @@ func Noon(observer Observer, date time.Time) time.Time {
 //
 //	Date and time at which midnight occurs.
 func Midnight(observer Observer, date time.Time) time.Time {
+	if observer.Location == nil {
+		return midnight_on_day(observer, date)
+	}
+	midnight, _ := on_observer_day(observer, date, func(day time.Time) (time.Time, error) {
+		return midnight_on_day(observer, day), nil
+	})
+	return midnight
+}
+
+// Calculate the solar midnight closest to the start of the day of the date.
+func midnight_on_day(observer Observer, date time.Time) time.Time {
 	date = time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, date.Location())
 	jd := julianday(date)
-	newt := jday_to_jcentury(jd + 0.5 + -observer.Longitude/360.0)
+	newt := jday_to_jcentury_tt(jd + 0.5 + -observer.Longitude/360.0)
 
 	eqtime := eq_of_time(newt)
 	timeUTC := (-observer.Longitude * 4.0) - eqtime
@@ -437,42 +581,28 @@ func Midnight(observer Observer, date time.Time) time.Time {
 	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, second, 0, time.UTC).In(date.Location())
 }
 
-func ZenithAndAzimuth(observer Observer, dateandtime time.Time, with_refraction bool) (float64, float64) {
-	latitude := observer.Latitude
-
-	if observer.Latitude > 89.8 {
-		latitude = 89.8
-	} else if observer.Latitude < -89.8 {
-		latitude = -89.8
-	}
-	longitude := observer.Longitude
-
-	utc_datetime := dateandtime.UTC()
-
-	timenow := (utc_datetime.Hour() + (utc_datetime.Minute() / 60.0) + (utc_datetime.Second() / 3600.0))
-
-	JD := julianday(dateandtime)
-	t := jday_to_jcentury(JD + float64(timenow)/24.0)
-	solarDec := sun_declination(t)
-	eqtime := eq_of_time(t)
-
-	solarTimeFix := eqtime - (4.0 * -longitude)
-	trueSolarTime := float64(utc_datetime.Hour()*60+utc_datetime.Minute()+utc_datetime.Second()/60) + solarTimeFix
-	//    in minutes as a float, fractional part is seconds
-
-	for trueSolarTime > 1440 {
-		trueSolarTime = trueSolarTime - 1440
-	}
-
-	hourangle := trueSolarTime/4.0 - 180.0
-	//    Thanks to Louis Schwarzmayr for the next line:
-	if hourangle < -180 {
-		hourangle = hourangle + 360.0
-	}
+// Calculate the time of day in hours, including the fractions of a second.
+func hours_since_midnight(t time.Time) float64 {
+	t = t.UTC()
+	seconds := float64(t.Second()) + float64(t.Nanosecond())/1e9
+	return float64(t.Hour()) + float64(t.Minute())/60.0 + seconds/3600.0
+}
 
+// Calculate the zenith and azimuth angle of a body with the given declination
+// and hour angle, as seen from the given latitude.
+// Args:
+//
+//	latitude:    The latitude of the observer
+//	declination: The declination of the body in degrees
+//	hourangle:   The hour angle of the body in degrees, in the range -180 to 180
+//
+// Returns:
+//
+//	The zenith angle and the azimuth angle clockwise from North, in degrees.
+func horizontal_coordinates(latitude, declination, hourangle float64) (float64, float64) {
 	harad := radians(hourangle)
 
-	csz := math.Sin(radians(latitude))*math.Sin(radians(solarDec)) + math.Cos(radians(latitude))*math.Cos(radians(solarDec))*math.Cos(harad)
+	csz := math.Sin(radians(latitude))*math.Sin(radians(declination)) + math.Cos(radians(latitude))*math.Cos(radians(declination))*math.Cos(harad)
 
 	if csz > 1.0 {
 		csz = 1.0
@@ -486,7 +616,7 @@ func ZenithAndAzimuth(observer Observer, dateandtime time.Time, with_refraction
 
 	azimuth := 0.0
 	if math.Abs(azDenom) > 0.001 {
-		azRad := ((math.Sin(radians(latitude)) * math.Cos(radians(zenith))) - math.Sin(radians(solarDec))) / azDenom
+		azRad := ((math.Sin(radians(latitude)) * math.Cos(radians(zenith))) - math.Sin(radians(declination))) / azDenom
 
 		if math.Abs(azRad) > 1.0 {
 			if azRad < 0 {
@@ -511,8 +641,52 @@ func ZenithAndAzimuth(observer Observer, dateandtime time.Time, with_refraction
 	if azimuth < 0.0 {
 		azimuth = azimuth + 360.0
 	}
+	return zenith, azimuth
+}
+
+// Calculate the zenith and azimuth angle of the sun.
+// Args:
+//
+//	observer:        Observer to calculate the position for
+//	dateandtime:     The instant for which to calculate the angles, fractions of a second are taken into account.
+//	with_refraction: If true adjust zenith to take refraction into account
+//
+// Returns:
+//
+//	The zenith angle and the azimuth angle clockwise from North, in degrees.
+func ZenithAndAzimuth(observer Observer, dateandtime time.Time, with_refraction bool) (float64, float64) {
+	latitude := observer.Latitude
+
+	if observer.Latitude > 89.8 {
+		latitude = 89.8
+	} else if observer.Latitude < -89.8 {
+		latitude = -89.8
+	}
+	longitude := observer.Longitude
+
+	timenow := hours_since_midnight(dateandtime)
+
+	t := jday_to_jcentury_tt(JulianDay(dateandtime))
+	solarDec := sun_declination(t)
+
+	solarTimeFix := apparent_solar_time_offset(longitude, t)
+	// in minutes as a float, fractional part is seconds
+	trueSolarTime := timenow*60.0 + solarTimeFix
+
+	for trueSolarTime > 1440 {
+		trueSolarTime = trueSolarTime - 1440
+	}
+
+	hourangle := trueSolarTime/4.0 - 180.0
+	//    Thanks to Louis Schwarzmayr for the next line:
+	if hourangle < -180 {
+		hourangle = hourangle + 360.0
+	}
+
+	zenith, azimuth := horizontal_coordinates(latitude, solarDec, hourangle)
+
 	if with_refraction {
-		zenith -= refraction_at_zenith(zenith)
+		zenith -= refraction_for_observer(observer, zenith)
 	}
 	return zenith, azimuth
 }
@@ -559,6 +733,57 @@ func Elevation(observer Observer, dateandtime time.Time, with_refraction bool) f
 	return 90.0 - Zenith(observer, dateandtime, with_refraction)
 }
 
+// SolarEphemeris contains the geocentric position of the sun.
+type SolarEphemeris struct {
+	// RightAscension in degrees (0-360).
+	RightAscension float64
+	// Declination in degrees, positive north of the celestial equator.
+	Declination float64
+	// EquationOfTime is the difference between apparent and mean solar time,
+	// positive when the sundial is ahead of the clock.
+	EquationOfTime time.Duration
+	// ApparentLongitude is the ecliptic longitude in degrees (0-360),
+	// corrected for nutation and aberration.
+	ApparentLongitude float64
+	// Obliquity of the ecliptic in degrees, corrected for nutation.
+	Obliquity float64
+	// Distance between the earth and the sun in AU.
+	Distance float64
+	// AngularRadius is the apparent radius of the solar disk in degrees.
+	AngularRadius float64
+}
+
+// Semidiameter of the sun at a distance of 1 AU in degrees.
+const sunSemidiameterAtOneAU = 959.63 / 3600.0
+
+func sun_ephemeris(juliancentury float64) SolarEphemeris {
+	distance := sun_rad_vector(juliancentury)
+	eot := eq_of_time(juliancentury)
+
+	return SolarEphemeris{
+		RightAscension:    properAngle(sun_rt_ascension(juliancentury)),
+		Declination:       sun_declination(juliancentury),
+		EquationOfTime:    time.Duration(eot * float64(time.Minute)),
+		ApparentLongitude: properAngle(sun_apparent_long(juliancentury)),
+		Obliquity:         obliquity_correction(juliancentury),
+		Distance:          distance,
+		AngularRadius:     sunSemidiameterAtOneAU / distance,
+	}
+}
+
+// Calculate the position of the sun.
+// Args:
+//
+//	dateandtime: The date and time for which to calculate the position.
+//
+// Returns:
+//
+//	The right ascension, declination, equation of time, apparent longitude,
+//	obliquity of the ecliptic, distance and angular radius of the sun.
+func SunEphemeris(dateandtime time.Time) SolarEphemeris {
+	return sun_ephemeris(jday_to_jcentury_tt(JulianDay(dateandtime)))
+}
+
 // Calculate dawn time.
 // Args:
 //
@@ -574,16 +799,35 @@ func Elevation(observer Observer, dateandtime time.Time, with_refraction bool) f
 func Dawn(observer Observer, date time.Time, depression float64) (time.Time, error) {
 	t, err := time_of_transit(observer, date, 90.0+depression, SunDirectionRising)
 	if err != nil {
-		return t, fmt.Errorf("sun never reaches %v degrees below the horizon, at this location", depression)
+		return t, event_error(err, depression_kind(depression, SunDirectionRising), -depression)
 	}
 	return t, nil
 }
 
 var (
+	// ErrAlwaysBelow matches an EventError when the sun doesn't rise to the elevation of the event.
 	ErrAlwaysBelow = errors.New("sun is always below the horizon on this day, at this location")
+	// ErrAlwaysAbove matches an EventError when the sun doesn't sink to the elevation of the event.
 	ErrAlwaysAbove = errors.New("sun is always above the horizon on this day, at this location")
 )
 
+// Find the kind of a dawn or dusk with the given depression.
+func depression_kind(depression float64, direction SunDirection) EventKind {
+	kinds := map[float64][2]EventKind{
+		DepressionCivil:        {EventDawnCivil, EventDuskCivil},
+		DepressionNautical:     {EventDawnNautical, EventDuskNautical},
+		DepressionAstronomical: {EventDawnAstronomical, EventDuskAstronomical},
+	}
+	k, ok := kinds[depression]
+	if !ok {
+		return 0
+	}
+	if direction == SunDirectionRising {
+		return k[0]
+	}
+	return k[1]
+}
+
 // Calculate sunrise time.
 // Args:
 //
@@ -595,14 +839,15 @@ var (
 //
 //	Date and time at which sunrise occurs.
 func Sunrise(observer Observer, date time.Time) (time.Time, error) {
-	t, err := time_of_transit(observer, date, 90.0+sunApperentRadius, SunDirectionRising)
+	return DefaultDefinitions().Sunrise(observer, date)
+}
+
+// Sunrise calculates the sunrise with the apparent radius of the definitions.
+func (d Definitions) Sunrise(observer Observer, date time.Time) (time.Time, error) {
+	t, err := time_of_transit(observer, date, 90.0+d.SunApparentRadius, SunDirectionRising)
 
 	if err != nil {
-		z := Zenith(observer, Noon(observer, date), true)
-		if z > 90.0 {
-			return time.Time{}, ErrAlwaysBelow
-		}
-		return time.Time{}, ErrAlwaysAbove
+		return time.Time{}, event_error(err, EventSunrise, -d.SunApparentRadius)
 	}
 
 	return t, nil
@@ -621,7 +866,7 @@ func Sunrise(observer Observer, date time.Time) (time.Time, error) {
 //
 // Raises:
 //
-//	    ValueError: if the sun does not reach the horizon
+//	    EventError: if the sun does not reach the horizon
 //
 //		if isinstance(tzinfo, str) {
 //			tzinfo = pytz.timezone(tzinfo)
@@ -630,13 +875,14 @@ func Sunrise(observer Observer, date time.Time) (time.Time, error) {
 //			date := today(tzinfo)
 //		}
 func Sunset(observer Observer, date time.Time) (time.Time, error) {
-	t, err := time_of_transit(observer, date, 90.0+sunApperentRadius, SunDirectionSetting)
+	return DefaultDefinitions().Sunset(observer, date)
+}
+
+// Sunset calculates the sunset with the apparent radius of the definitions.
+func (d Definitions) Sunset(observer Observer, date time.Time) (time.Time, error) {
+	t, err := time_of_transit(observer, date, 90.0+d.SunApparentRadius, SunDirectionSetting)
 	if err != nil {
-		z := Zenith(observer, Noon(observer, date), true)
-		if z > 90.0 {
-			return time.Time{}, ErrAlwaysBelow
-		}
-		return time.Time{}, ErrAlwaysAbove
+		return time.Time{}, event_error(err, EventSunset, -d.SunApparentRadius)
 	}
 	return t, nil
 
@@ -655,7 +901,7 @@ func Sunset(observer Observer, date time.Time) (time.Time, error) {
 //     Date and time at which dusk occurs.
 
 // Raises:
-//     ValueError: if dusk does not occur on the specified date
+//     EventError: if dusk does not occur on the specified date
 //
 
 //	if isinstance(tzinfo, str) {
@@ -668,7 +914,7 @@ func Sunset(observer Observer, date time.Time) (time.Time, error) {
 func Dusk(observer Observer, date time.Time, depression float64) (time.Time, error) {
 	t, err := time_of_transit(observer, date, 90.0+depression, SunDirectionSetting)
 	if err != nil {
-		return t, fmt.Errorf("sun never reaches %v degrees below the horizon, at this location", depression)
+		return t, event_error(err, depression_kind(depression, SunDirectionSetting), -depression)
 	}
 	return t, nil
 }
@@ -686,13 +932,18 @@ func Dusk(observer Observer, date time.Time, depression float64) (time.Time, err
 //
 // Raises:
 //
-//	ValueError: if the sun does not rise or does not set
+//	EventError: if the sun does not rise or does not set
 func Daylight(observer Observer, date time.Time) (time.Time, time.Time, error) {
-	start, err := Sunrise(observer, date)
+	return DefaultDefinitions().Daylight(observer, date)
+}
+
+// Daylight calculates the daylight with the apparent radius of the definitions.
+func (d Definitions) Daylight(observer Observer, date time.Time) (time.Time, time.Time, error) {
+	start, err := d.Sunrise(observer, date)
 	if err != nil {
 		return time.Time{}, time.Time{}, err
 	}
-	end, err := Sunset(observer, date)
+	end, err := d.Sunset(observer, date)
 	if err != nil {
 		return time.Time{}, time.Time{}, err
 	}
@@ -715,7 +966,7 @@ func Daylight(observer Observer, date time.Time) (time.Time, time.Time, error) {
 //
 // Raises:
 //
-//	ValueError: if dawn does not occur on the specified date or
+//	EventError: if dawn does not occur on the specified date or
 //	            dusk on the following day
 func Night(observer Observer, date time.Time) (time.Time, time.Time, error) {
 	start, err := Dusk(observer, date, 6)
@@ -749,19 +1000,24 @@ func Night(observer Observer, date time.Time) (time.Time, time.Time, error) {
 //
 // Raises:
 //
-//	ValueError: if the sun does not rise or does not set
+//	EventError: if the sun does not rise or does not set
 func Twilight(observer Observer, date time.Time, direction SunDirection) (time.Time, time.Time, error) {
+	return DefaultDefinitions().Twilight(observer, date, direction)
+}
+
+// Twilight calculates the twilight with the apparent radius of the definitions.
+func (d Definitions) Twilight(observer Observer, date time.Time, direction SunDirection) (time.Time, time.Time, error) {
 	start, err := time_of_transit(observer, date, 90+6, direction)
 	if err != nil {
-		return time.Time{}, time.Time{}, err
+		return time.Time{}, time.Time{}, event_error(err, depression_kind(DepressionCivil, direction), -6)
 	}
 
-	end, err := Sunset(observer, date)
+	end, err := d.Sunset(observer, date)
 	if err != nil {
 		return time.Time{}, time.Time{}, err
 	}
 	if direction == SunDirectionRising {
-		end, err := Sunrise(observer, date)
+		end, err := d.Sunrise(observer, date)
 		if err != nil {
 			return time.Time{}, time.Time{}, err
 		}
@@ -790,15 +1046,25 @@ func Twilight(observer Observer, date time.Time, direction SunDirection) (time.T
 //
 // Raises:
 //
-//	ValueError: if the sun does not transit the elevations -4 & +6 degrees
+//	EventError: if the sun does not transit the elevations -4 & +6 degrees
 func GoldenHour(observer Observer, date time.Time, direction SunDirection) (time.Time, time.Time, error) {
-	start, err := time_of_transit(observer, date, 90+4, direction)
+	return DefaultDefinitions().GoldenHour(observer, date, direction)
+}
+
+// GoldenHour calculates the golden hour between the elevations of the definitions.
+func (d Definitions) GoldenHour(observer Observer, date time.Time, direction SunDirection) (time.Time, time.Time, error) {
+	lowKind, highKind := EventGoldenHourRisingStart, EventGoldenHourRisingEnd
+	if direction == SunDirectionSetting {
+		lowKind, highKind = EventGoldenHourSettingEnd, EventGoldenHourSettingStart
+	}
+
+	start, err := time_of_transit(observer, date, 90-d.GoldenHourLower, direction)
 	if err != nil {
-		return time.Time{}, time.Time{}, err
+		return time.Time{}, time.Time{}, event_error(err, lowKind, d.GoldenHourLower)
 	}
-	end, err := time_of_transit(observer, date, 90-6, direction)
+	end, err := time_of_transit(observer, date, 90-d.GoldenHourUpper, direction)
 	if err != nil {
-		return time.Time{}, time.Time{}, err
+		return time.Time{}, time.Time{}, event_error(err, highKind, d.GoldenHourUpper)
 	}
 
 	if direction == SunDirectionRising {
@@ -826,15 +1092,26 @@ func GoldenHour(observer Observer, date time.Time, direction SunDirection) (time
 
 // Raises:
 //
-//	ValueError: if the sun does not transit the elevations -4 & -6 degrees
+//	EventError: if the sun does not transit the elevations -4 & -6 degrees
 func BlueHour(observer Observer, date time.Time, direction SunDirection) (time.Time, time.Time, error) {
-	start, err := time_of_transit(observer, date, 90+6, direction)
+	return DefaultDefinitions().BlueHour(observer, date, direction)
+}
+
+// BlueHour calculates the blue hour between the elevations of the definitions.
+func (d Definitions) BlueHour(observer Observer, date time.Time, direction SunDirection) (time.Time, time.Time, error) {
+	date = observer_date(observer, date)
+	lowKind, highKind := EventBlueHourRisingStart, EventBlueHourRisingEnd
+	if direction == SunDirectionSetting {
+		lowKind, highKind = EventBlueHourSettingEnd, EventBlueHourSettingStart
+	}
+
+	start, err := time_of_transit(observer, date, 90-d.BlueHourLower, direction)
 	if err != nil {
-		return time.Time{}, time.Time{}, err
+		return time.Time{}, time.Time{}, event_error(err, lowKind, d.BlueHourLower)
 	}
-	end, err := time_of_transit(observer, date, 90+4, direction)
+	end, err := time_of_transit(observer, date, 90-d.BlueHourUpper, direction)
 	if err != nil {
-		return time.Time{}, time.Time{}, err
+		return time.Time{}, time.Time{}, event_error(err, highKind, d.BlueHourUpper)
 	}
 
 	if direction == SunDirectionRising {
@@ -844,64 +1121,52 @@ func BlueHour(observer Observer, date time.Time, direction SunDirection) (time.T
 
 }
 
-// TODO: not working correctly
-// func Rahukaalam(observer Observer, date time.Time, daytime bool) (time.Time, time.Time, error) {
-// 	// Calculate ruhakaalam times.
-
-// 	// Args:
-// 	//     observer Observer to calculate rahukaalam for
-// 	//     date:     Date to calculate for.
-// 	//     daytime:  If True calculate for the day time else calculate for the night time.
-// 	//     tzinfo:   Timezone to return times in. Default is UTC.
-
-// 	// Returns:
-// 	//     Tuple containing the start and end times for Rahukaalam.
-
-// 	// Raises:
-// 	//     ValueError: if the sun does not rise or does not set
-// 	//
-
-// 	start, err := Sunrise(observer, date)
-// 	if err != nil {
-// 		return time.Time{}, time.Time{}, err
-// 	}
-// 	end, err := Sunset(observer, date)
-// 	if err != nil {
-// 		return time.Time{}, time.Time{}, err
-// 	}
-
-// 	if !daytime {
-// 		start, err = Sunset(observer, date)
-// 		if err != nil {
-// 			return time.Time{}, time.Time{}, err
-// 		}
-// 		end, err = Sunrise(observer, date.Add(24*time.Hour))
-// 		if err != nil {
-// 			return time.Time{}, time.Time{}, err
-// 		}
-// 	}
-
-// 	octant_duration := end.Sub(start).Seconds() / 8
-// 	// octant_duration := datetime.timedelta((end - start).seconds / 8)
-
-// 	// Mo,Sa,Fr,We,Th,Tu,Su
-// 	octant_index := []int{1, 6, 4, 5, 3, 2, 7}
-
-// 	weekday := date.Weekday()
-
-// 	// convert to python weekday
-// 	if weekday == time.Sunday {
-// 		weekday = 6
-// 	} else {
-// 		weekday--
-// 	}
-
-// 	octant := octant_index[weekday]
-
-// 	// TODO: is seconds correct?
-// 	add := time.Duration(octant_duration*float64(octant)) * time.Second
-// 	start = start.Add(add)
-// 	end = start.Add(time.Duration(octant_duration))
-
-// 	return start.In(date.Location()), end.In(date.Location()), nil
-// }
+// Calculate rahukaalam times.
+// Args:
+//
+//	observer: Observer to calculate rahukaalam for
+//	date:     Date to calculate for.
+//	daytime:  If true calculate for the day time else calculate for the night time.
+//
+// Returns:
+//
+//	The start and end times for Rahukaalam.
+//
+// Raises:
+//
+//	ErrAlwaysAbove or ErrAlwaysBelow: if the sun does not rise or does not set
+func Rahukaalam(observer Observer, date time.Time, daytime bool) (time.Time, time.Time, error) {
+	return DefaultDefinitions().Rahukaalam(observer, date, daytime)
+}
+
+// Rahukaalam calculates rahukaalam with the apparent radius of the definitions.
+func (d Definitions) Rahukaalam(observer Observer, date time.Time, daytime bool) (time.Time, time.Time, error) {
+	date = observer_date(observer, date)
+	start, err := d.Sunrise(observer, date)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := d.Sunset(observer, date)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if !daytime {
+		start = end
+		end, err = d.Sunrise(observer, date.AddDate(0, 0, 1))
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	octantDuration := end.Sub(start) / 8
+
+	// Su,Mo,Tu,We,Th,Fr,Sa
+	octantIndex := []int{7, 1, 6, 4, 5, 3, 2}
+	octant := octantIndex[date.Weekday()]
+
+	start = start.Add(octantDuration * time.Duration(octant))
+	end = start.Add(octantDuration)
+
+	return start.In(date.Location()), end.In(date.Location()), nil
+}
@@ -1,24 +1,12 @@
 package astral
 
 import (
+	"errors"
 	"math"
 	"testing"
 	"time"
 )
 
-func nextEvent(t *testing.T, obs Observer, dt time.Time, event func(observer Observer, date time.Time) (time.Time, error)) time.Time {
-	for offset := 0; offset < 366; offset++ {
-		newdate := dt.Add(time.Duration(time.Duration(offset) * 24 * time.Hour))
-		ti, err := event(obs, newdate)
-		if err == nil {
-			return ti
-		}
-	}
-
-	t.Fatalf("should not happen")
-	return dt
-}
-
 func absDuration(n time.Duration) time.Duration {
 	if n < 0 {
 		return -n
@@ -60,8 +48,14 @@ func TestNorwaySunUp(t *testing.T) {
 	}
 
 	// Find the next sunset and sunrise:
-	nextSunrise := nextEvent(t, obs, june, Sunrise)
-	nextSunset := nextEvent(t, obs, june, Sunset)
+	nextSunrise, err := NextEvent(obs, june, EventSunrise)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nextSunset, err := NextEvent(obs, june, EventSunset)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if !nextSunrise.After(nextSunset) {
 		t.FailNow()
@@ -291,53 +285,62 @@ func TestTwilight(t *testing.T) {
 
 var newDelhi = Observer{Latitude: 28.644800, Longitude: 77.216721}
 
-// TODO: FIXME
-// func TestRahukaalam(t *testing.T) {
-// 	type args struct {
-// 		observer Observer
-// 		date     time.Time
-// 		daytime  bool
-// 	}
-// 	tests := []struct {
-// 		name      string
-// 		args      args
-// 		wantStart time.Time
-// 		wantEnd   time.Time
-// 		wantErr   bool
-// 	}{
-
-// 		// 	datetime.date(2015, 12, 1),
-// 		// 	(
-// 		// 		datetime.datetime(2015, 12, 1, 9, 17),
-// 		// 		datetime.datetime(2015, 12, 1, 10, 35),
-// 		// 	),
-// 		// ),
-// 		// (
-// 		// 	datetime.date(2015, 12, 2),
-// 		// 	(
-// 		// 		datetime.datetime(2015, 12, 2, 6, 40),
-// 		// 		datetime.datetime(2015, 12, 2, 7, 58),
-// 		// 	),
-
-// 		// Day
-// 		// {args: args{daytime: true, observer: newDelhi, date: time.Date(2015, 12, 1, 0, 0, 0, 0, time.UTC)}, wantStart: time.Date(2015, 12, 1, 9, 17, 0, 0, time.UTC), wantEnd: time.Date(2015, 12, 1, 10, 35, 0, 0, time.UTC)},
-// 		// {args: args{daytime: true, observer: london, date: time.Date(2021, 4, 16, 0, 0, 0, 0, time.UTC)}, wantStart: time.Date(2021, 4, 16, 10, 15, 37, 0, time.UTC), wantEnd: time.Date(2021, 4, 16, 12, 0, 14, 0, time.UTC)},
-
-// 		// Night
-// 		// {args: args{daytime: true, observer: newDelhi, date: time.Date(2015, 12, 2, 0, 0, 0, 0, time.UTC)}, wantStart: time.Date(2015, 12, 2, 6, 40, 0, 0, time.UTC), wantEnd: time.Date(2015, 12, 2, 7, 58, 0, 0, time.UTC)},
-// 	}
-// 	for _, tt := range tests {
-// 		t.Run(tt.name, func(t *testing.T) {
-// 			start, end, err := Rahukaalam(tt.args.observer, tt.args.date, tt.args.daytime)
-// 			if (err != nil) != tt.wantErr {
-// 				t.Errorf("Dawn() error = %v, wantErr %v", err, tt.wantErr)
-// 				return
-// 			}
-// 			require.True(t, almostEqual(start, tt.wantStart, 60*time.Second), "start want: %v but got: %v", tt.wantStart, start)
-// 			require.True(t, almostEqual(end, tt.wantEnd, 60*time.Second), "end want: %v but got: %v", tt.wantEnd, end)
-// 		})
-// 	}
-// }
+var sydney = Observer{Latitude: -33.86, Longitude: 151.21}
+var reykjavik = Observer{Latitude: 64.15, Longitude: -21.94}
+
+func TestRahukaalam(t *testing.T) {
+	europeLondon, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+	londonWithLocation := london
+	londonWithLocation.Location = europeLondon
+
+	type args struct {
+		observer Observer
+		date     time.Time
+		daytime  bool
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   bool
+	}{
+		// Day, all weekdays
+		{args: args{daytime: true, observer: newDelhi, date: time.Date(2015, 12, 1, 0, 0, 0, 0, time.UTC)}, wantStart: time.Date(2015, 12, 1, 9, 17, 0, 0, time.UTC), wantEnd: time.Date(2015, 12, 1, 10, 35, 0, 0, time.UTC)},
+		{args: args{daytime: true, observer: newDelhi, date: time.Date(2015, 12, 2, 0, 0, 0, 0, time.UTC)}, wantStart: time.Date(2015, 12, 2, 6, 40, 0, 0, time.UTC), wantEnd: time.Date(2015, 12, 2, 7, 58, 0, 0, time.UTC)},
+		{args: args{daytime: true, observer: newDelhi, date: time.Date(2015, 12, 3, 0, 0, 0, 0, time.UTC)}, wantStart: time.Date(2015, 12, 3, 7, 59, 0, 0, time.UTC), wantEnd: time.Date(2015, 12, 3, 9, 17, 0, 0, time.UTC)},
+		{args: args{daytime: true, observer: newDelhi, date: time.Date(2015, 12, 4, 0, 0, 0, 0, time.UTC)}, wantStart: time.Date(2015, 12, 4, 5, 23, 0, 0, time.UTC), wantEnd: time.Date(2015, 12, 4, 6, 41, 0, 0, time.UTC)},
+		{args: args{daytime: true, observer: newDelhi, date: time.Date(2015, 12, 5, 0, 0, 0, 0, time.UTC)}, wantStart: time.Date(2015, 12, 5, 4, 5, 0, 0, time.UTC), wantEnd: time.Date(2015, 12, 5, 5, 23, 0, 0, time.UTC)},
+		{args: args{daytime: true, observer: newDelhi, date: time.Date(2015, 12, 6, 0, 0, 0, 0, time.UTC)}, wantStart: time.Date(2015, 12, 6, 10, 36, 0, 0, time.UTC), wantEnd: time.Date(2015, 12, 6, 11, 54, 0, 0, time.UTC)},
+		{args: args{daytime: true, observer: newDelhi, date: time.Date(2015, 12, 7, 0, 0, 0, 0, time.UTC)}, wantStart: time.Date(2015, 12, 7, 2, 49, 0, 0, time.UTC), wantEnd: time.Date(2015, 12, 7, 4, 6, 0, 0, time.UTC)},
+		// Day, other latitudes
+		{args: args{daytime: true, observer: london, date: time.Date(2015, 12, 1, 0, 0, 0, 0, time.UTC)}, wantStart: time.Date(2015, 12, 1, 13, 52, 0, 0, time.UTC), wantEnd: time.Date(2015, 12, 1, 14, 53, 0, 0, time.UTC)},
+		{args: args{daytime: true, observer: reykjavik, date: time.Date(2015, 12, 7, 0, 0, 0, 0, time.UTC)}, wantStart: time.Date(2015, 12, 7, 11, 36, 0, 0, time.UTC), wantEnd: time.Date(2015, 12, 7, 12, 10, 0, 0, time.UTC)},
+		{args: args{daytime: true, observer: sydney, date: time.Date(2015, 12, 2, 0, 0, 0, 0, time.UTC)}, wantStart: time.Date(2015, 12, 2, 1, 44, 0, 0, time.UTC), wantEnd: time.Date(2015, 12, 2, 3, 31, 0, 0, time.UTC)},
+		// Night
+		{args: args{daytime: false, observer: newDelhi, date: time.Date(2015, 12, 1, 0, 0, 0, 0, time.UTC)}, wantStart: time.Date(2015, 12, 1, 22, 4, 0, 0, time.UTC), wantEnd: time.Date(2015, 12, 1, 23, 45, 0, 0, time.UTC)},
+		{args: args{daytime: false, observer: london, date: time.Date(2015, 12, 6, 0, 0, 0, 0, time.UTC)}, wantStart: time.Date(2015, 12, 7, 5, 52, 0, 0, time.UTC), wantEnd: time.Date(2015, 12, 7, 7, 51, 0, 0, time.UTC)},
+		{args: args{daytime: false, observer: reykjavik, date: time.Date(2015, 12, 4, 0, 0, 0, 0, time.UTC)}, wantStart: time.Date(2015, 12, 4, 22, 55, 0, 0, time.UTC), wantEnd: time.Date(2015, 12, 5, 1, 19, 0, 0, time.UTC)},
+		// Night, the clocks are set back and the local day has 25 hours
+		{args: args{daytime: false, observer: londonWithLocation, date: time.Date(2015, 10, 25, 0, 30, 0, 0, europeLondon)}, wantStart: time.Date(2015, 10, 26, 4, 58, 0, 0, time.UTC), wantEnd: time.Date(2015, 10, 26, 6, 43, 0, 0, time.UTC)},
+		// Error
+		{wantErr: true, args: args{daytime: true, observer: Observer{Latitude: 69.6, Longitude: 18.8}, date: time.Date(2019, 6, 5, 0, 0, 0, 0, time.UTC)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := Rahukaalam(tt.args.observer, tt.args.date, tt.args.daytime)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Rahukaalam() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			almostEqualTime(t, start, tt.wantStart, 60*time.Second)
+			almostEqualTime(t, end, tt.wantEnd, 60*time.Second)
+		})
+	}
+}
 
 func TestElevation(t *testing.T) {
 	type args struct {
@@ -386,6 +389,38 @@ func TestAzimuth(t *testing.T) {
 	}
 }
 
+func TestZenithAndAzimuthSmooth(t *testing.T) {
+	start := time.Date(2015, 12, 14, 9, 0, 0, 0, time.UTC)
+
+	var elevationSteps, azimuthSteps []float64
+	prevZenith, prevAzimuth := ZenithAndAzimuth(london, start, false)
+	for step := 1; step <= 60; step++ {
+		zenith, azimuth := ZenithAndAzimuth(london, start.Add(time.Duration(step)*time.Second), false)
+		elevationSteps = append(elevationSteps, prevZenith-zenith)
+		azimuthSteps = append(azimuthSteps, azimuth-prevAzimuth)
+		prevZenith, prevAzimuth = zenith, azimuth
+	}
+
+	// In the morning, the sun rises and moves from East to South with a nearly constant speed.
+	for _, steps := range [][]float64{elevationSteps, azimuthSteps} {
+		for _, s := range steps {
+			if s <= 0 {
+				t.Fatalf("not monotonic: %v", steps)
+			}
+			almostEqualFloat(t, steps[0], s, steps[0]*0.01)
+		}
+	}
+
+	// fractions of a second are taken into account
+	zenith := Zenith(london, start, false)
+	zenithHalf := Zenith(london, start.Add(500*time.Millisecond), false)
+	zenithFull := Zenith(london, start.Add(time.Second), false)
+	if !(zenith > zenithHalf && zenithHalf > zenithFull) {
+		t.Fatalf("not monotonic: %v, %v, %v", zenith, zenithHalf, zenithFull)
+	}
+	almostEqualFloat(t, (zenith+zenithFull)/2, zenithHalf, 0.0000001)
+}
+
 func TestZenith(t *testing.T) {
 	type args struct {
 		observer   Observer
@@ -598,3 +633,264 @@ func TestAdjustToObscuringFeature(t *testing.T) {
 		})
 	}
 }
+
+func TestObstruction(t *testing.T) {
+	date := time.Date(2015, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	// A ridge 500m above and 2km away from the observer raises the horizon by ~14 degrees.
+	valley := london
+	valley.Obstruction = &Obstruction{Height: 500, Distance: 2000}
+	ridge := degrees(math.Atan2(500, 2000))
+
+	sunrise, err := Sunrise(valley, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := TimeAtElevation(london, ridge-sunApperentRadius, date, SunDirectionRising)
+	if err != nil {
+		t.Fatal(err)
+	}
+	almostEqualTime(t, sunrise, want, time.Second)
+
+	sunset, err := Sunset(valley, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err = TimeAtElevation(london, ridge-sunApperentRadius, date, SunDirectionSetting)
+	if err != nil {
+		t.Fatal(err)
+	}
+	almostEqualTime(t, sunset, want, time.Second)
+
+	dawn, err := Dawn(valley, date, DepressionCivil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err = TimeAtElevation(london, ridge-DepressionCivil, date, SunDirectionRising)
+	if err != nil {
+		t.Fatal(err)
+	}
+	almostEqualTime(t, dawn, want, time.Second)
+
+	// A feature below the observer lowers the horizon.
+	cliff := london
+	cliff.Obstruction = &Obstruction{Height: -500, Distance: 2000}
+
+	unobstructed, err := Sunrise(london, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	earlier, err := Sunrise(cliff, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !earlier.Before(unobstructed) || !unobstructed.Before(sunrise) {
+		t.Fatalf("unexpected order: %v, %v, %v", earlier, unobstructed, sunrise)
+	}
+
+	// The sun doesn't climb above a ridge at ~27 degrees in London's winter.
+	valley.Obstruction = &Obstruction{Height: 1000, Distance: 2000}
+	if _, err := Sunrise(valley, date); !errors.Is(err, ErrAlwaysBelow) {
+		t.Fatalf("expected %v, got %v", ErrAlwaysBelow, err)
+	}
+}
+
+func TestSunEphemeris(t *testing.T) {
+	// Meeus, Astronomical Algorithms, Example 25.a: 1992 October 13, 0h TD
+	got := sun_ephemeris(-0.072183436)
+	almostEqualFloat(t, 199.90895, got.ApparentLongitude, 0.002)
+	almostEqualFloat(t, 198.38083, got.RightAscension, 0.002)
+	almostEqualFloat(t, -7.78507, got.Declination, 0.002)
+	almostEqualFloat(t, 0.99766, got.Distance, 0.00001)
+	almostEqualFloat(t, 23.43999, got.Obliquity, 0.0001)
+	almostEqualFloat(t, 0.26719, got.AngularRadius, 0.00001)
+
+	// Meeus, Astronomical Algorithms, Example 28.a: 1992 October 13, 0h TD
+	almostEqualTime(t,
+		time.Time{}.Add(13*time.Minute+42*time.Second+600*time.Millisecond),
+		time.Time{}.Add(got.EquationOfTime),
+		2*time.Second)
+
+	// the sundial is behind the clock in February and ahead in November
+	feb := SunEphemeris(time.Date(2024, 2, 11, 12, 0, 0, 0, time.UTC))
+	almostEqualTime(t, time.Time{}.Add(-14*time.Minute-14*time.Second), time.Time{}.Add(feb.EquationOfTime), 10*time.Second)
+
+	// the sun is closest to the earth at the beginning of January
+	perihelion := SunEphemeris(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC))
+	aphelion := SunEphemeris(time.Date(2024, 7, 5, 0, 0, 0, 0, time.UTC))
+	almostEqualFloat(t, 0.98333, perihelion.Distance, 0.0001)
+	almostEqualFloat(t, 1.01672, aphelion.Distance, 0.0001)
+	if perihelion.AngularRadius <= aphelion.AngularRadius {
+		t.Fatalf("expected a larger sun at perihelion: %v <= %v", perihelion.AngularRadius, aphelion.AngularRadius)
+	}
+}
+
+func TestAtmosphere(t *testing.T) {
+	date := time.Date(2015, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	standard := london
+	standard.Atmosphere = &Atmosphere{Pressure: 1010, Temperature: 10}
+	vacuum := london
+	vacuum.NoRefraction = true
+	cold := london
+	cold.Atmosphere = &Atmosphere{Pressure: 1040, Temperature: -20}
+
+	// the standard atmosphere is the default
+	for _, fn := range []func(Observer, time.Time) (time.Time, error){Sunrise, Sunset} {
+		want, _ := fn(london, date)
+		got, err := fn(standard, date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+	noon := Noon(london, date)
+	almostEqualFloat(t, Elevation(london, noon, true), Elevation(standard, noon, true), 1e-9)
+
+	// without refraction the sun rises later and sets earlier
+	sunrise, _ := Sunrise(london, date)
+	sunset, _ := Sunset(london, date)
+	vacuumSunrise, err := Sunrise(vacuum, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vacuumSunset, err := Sunset(vacuum, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := vacuumSunrise.Sub(sunrise); d < 2*time.Minute || d > 6*time.Minute {
+		t.Fatalf("unexpected difference %v", d)
+	}
+	if d := sunset.Sub(vacuumSunset); d < 2*time.Minute || d > 6*time.Minute {
+		t.Fatalf("unexpected difference %v", d)
+	}
+	// the upper limb touches the geometric horizon
+	almostEqualFloat(t, -sunApperentRadius, Elevation(vacuum, vacuumSunrise, true), 0.02)
+	almostEqualFloat(t, Elevation(london, noon, false), Elevation(vacuum, noon, true), 1e-9)
+
+	// cold and dense air bends the light more
+	coldSunrise, err := Sunrise(cold, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !coldSunrise.Before(sunrise) {
+		t.Fatalf("expected sunrise %v before %v", coldSunrise, sunrise)
+	}
+	if Elevation(cold, noon, true) <= Elevation(london, noon, true) {
+		t.Fatal("expected a higher apparent elevation")
+	}
+
+	// the moon is refracted as well
+	almostEqualFloat(t, MoonElevation(london, noon, false), MoonElevation(vacuum, noon, true), 1e-9)
+
+	// invalid atmospheres are replaced by the standard one
+	for _, atmosphere := range []Atmosphere{
+		{},
+		{Pressure: -1010, Temperature: 10},
+		{Pressure: 1010, Temperature: -273},
+		{Pressure: 1010, Temperature: -300},
+		{Pressure: math.NaN(), Temperature: 10},
+	} {
+		if err := atmosphere.Validate(); !errors.Is(err, ErrInvalidAtmosphere) {
+			t.Fatalf("%+v: got %v, want %v", atmosphere, err, ErrInvalidAtmosphere)
+		}
+		invalid := london
+		invalid.Atmosphere = &atmosphere
+		got, err := Sunrise(invalid, date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(sunrise) {
+			t.Fatalf("%+v: got %v, want %v", atmosphere, got, sunrise)
+		}
+	}
+	if err := cold.Atmosphere.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestObserverLocation(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kiritimati, err := time.LoadLocation("Pacific/Kiritimati")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		observer Observer
+		date     time.Time
+		sunrise  time.Time
+		sunset   time.Time
+	}{
+		{
+			name:     "tokyo",
+			observer: Observer{Latitude: 35.6762, Longitude: 139.6503, Location: tokyo},
+			date:     time.Date(2026, 6, 1, 0, 0, 0, 0, tokyo),
+			sunrise:  time.Date(2026, 6, 1, 4, 27, 19, 0, tokyo),
+			sunset:   time.Date(2026, 6, 1, 18, 51, 23, 0, tokyo),
+		},
+		{
+			name:     "tokyo date in utc",
+			observer: Observer{Latitude: 35.6762, Longitude: 139.6503, Location: tokyo},
+			// June 2nd in Tokyo
+			date:    time.Date(2026, 6, 1, 20, 0, 0, 0, time.UTC),
+			sunrise: time.Date(2026, 6, 2, 4, 26, 59, 0, tokyo),
+			sunset:  time.Date(2026, 6, 2, 18, 52, 0, 0, tokyo),
+		},
+		{
+			// UTC+14, the sunrise of the UTC day is on the next local day
+			name:     "kiritimati",
+			observer: Observer{Latitude: 1.87, Longitude: -157.4, Location: kiritimati},
+			date:     time.Date(2026, 6, 1, 12, 0, 0, 0, kiritimati),
+			sunrise:  time.Date(2026, 6, 1, 6, 20, 55, 0, kiritimati),
+			sunset:   time.Date(2026, 6, 1, 18, 33, 52, 0, kiritimati),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sunrise, err := Sunrise(tc.observer, tc.date)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sunset, err := Sunset(tc.observer, tc.date)
+			if err != nil {
+				t.Fatal(err)
+			}
+			almostEqualTime(t, sunrise, tc.sunrise, time.Second)
+			almostEqualTime(t, sunset, tc.sunset, time.Second)
+			if sunrise.Location() != tc.observer.Location || sunset.Location() != tc.observer.Location {
+				t.Fatalf("got locations %v and %v, want %v", sunrise.Location(), sunset.Location(), tc.observer.Location)
+			}
+
+			noon := Noon(tc.observer, tc.date)
+			if noon.Before(sunrise) || noon.After(sunset) {
+				t.Fatalf("noon %v isn't between sunrise %v and sunset %v", noon, sunrise, sunset)
+			}
+
+			// the solar midnight is on the same day in the location, even if it's
+			// closer to the end of the day than to its start
+			midnight := Midnight(tc.observer, tc.date)
+			if midnight.Location() != tc.observer.Location {
+				t.Fatalf("got location %v, want %v", midnight.Location(), tc.observer.Location)
+			}
+			if got, want := midnight.Format("2006-01-02"), tc.sunrise.Format("2006-01-02"); got != want {
+				t.Fatalf("got midnight %v, want on %v", midnight, want)
+			}
+
+			next, err := NextEvent(tc.observer, tc.date, EventSunrise)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next.Location() != tc.observer.Location {
+				t.Fatalf("got location %v, want %v", next.Location(), tc.observer.Location)
+			}
+		})
+	}
+}